@@ -3,13 +3,27 @@ package main
 import (
 	"context"
 	_ "embed"
+	"errors"
+	"log/slog"
+	"strconv"
+	"sync"
+	"time"
 
 	wailsRuntime "github.com/wailsapp/wails/v2/pkg/runtime"
 
 	"tarkov-account-switcher/internal/accounts"
+	"tarkov-account-switcher/internal/actions"
+	"tarkov-account-switcher/internal/api"
+	"tarkov-account-switcher/internal/apperror"
 	"tarkov-account-switcher/internal/config"
+	"tarkov-account-switcher/internal/doctor"
+	"tarkov-account-switcher/internal/events"
 	"tarkov-account-switcher/internal/i18n"
 	"tarkov-account-switcher/internal/launcher"
+	"tarkov-account-switcher/internal/logging"
+	"tarkov-account-switcher/internal/notify"
+	"tarkov-account-switcher/internal/service"
+	"tarkov-account-switcher/internal/trayicon"
 	"tarkov-account-switcher/internal/updater"
 )
 
@@ -19,6 +33,24 @@ var trayIconData []byte
 // App struct — all public methods are bound to the frontend
 type App struct {
 	ctx context.Context
+
+	// opCtx is the lifecycle context for account operations; shutdown
+	// cancels it so a running switch is rolled back before the app exits
+	opCtx     context.Context
+	cancelOps context.CancelFunc
+
+	// pendingAction was given on the command line of this instance and
+	// runs once the tray is up; pendingActionErr is why it could not be
+	// parsed (broken link or shortcut) and is shown then instead
+	pendingAction    *actions.Action
+	pendingActionErr error
+
+	// Tray status: failure and update come from events, the rest is polled
+	trayMu     sync.Mutex
+	trayFailed bool
+	trayUpdate string
+	trayShown  trayState
+	trayIcons  map[trayicon.Status][]byte
 }
 
 func NewApp() *App {
@@ -27,6 +59,7 @@ func NewApp() *App {
 
 func (a *App) startup(ctx context.Context) {
 	a.ctx = ctx
+	a.opCtx, a.cancelOps = context.WithCancel(ctx)
 
 	// Load settings and set language
 	settings := config.GetSettings()
@@ -41,10 +74,49 @@ func (a *App) startup(ctx context.Context) {
 		wailsRuntime.EventsEmit(a.ctx, "session-captured", accountID)
 	}
 
+	// Post-switch verification result -> emit event to frontend
+	accounts.SessionVerifiedCallback = func(result accounts.VerifyResult) {
+		wailsRuntime.EventsEmit(a.ctx, "session-verified", map[string]interface{}{
+			"accountId": result.AccountID,
+			"accepted":  result.Accepted,
+			"message":   result.Message,
+		})
+	}
+
 	// Launcher started callback -> hide window
 	launcher.OnLauncherStarted = func() {
 		wailsRuntime.WindowHide(a.ctx)
 	}
+
+	// Switches requested over the HTTP API show up like forwarded actions
+	api.OnSwitched = func(result service.SwitchResultDTO) {
+		a.notifySwitchResult(result)
+	}
+	if settings.API.Enabled {
+		go func() {
+			if err := api.Start(a.opCtx); err != nil {
+				slog.Error("API not started", "err", err)
+			}
+		}()
+	}
+
+	// Keep the link handler pointing at this executable if it was moved
+	if settings.URIScheme {
+		go config.ApplyURIScheme(true)
+	}
+
+	// Desktop notifications for captures, timeouts, failures and old sessions
+	go a.watchNotifications()
+
+	// Step list while a switch runs
+	go a.forwardSwitchProgress()
+
+	// Leftovers from previous launcher runs - only safe while it is closed
+	go func() {
+		if !launcher.IsLauncherRunning() {
+			launcher.CleanTempFolder(false)
+		}
+	}()
 }
 
 func (a *App) domReady(ctx context.Context) {
@@ -54,17 +126,46 @@ func (a *App) domReady(ctx context.Context) {
 	// Start system tray
 	a.setupSystemTray()
 
+	if a.pendingActionErr != nil {
+		slog.Warn("start action rejected", "err", a.pendingActionErr)
+		// The window is open on a cold start, so it shows the error even
+		// if the tray icon is not up yet for the notification
+		a.notifyActionResult(apperror.Message(a.pendingActionErr), false)
+		a.pendingActionErr = nil
+	}
+	if a.pendingAction != nil {
+		go a.runAction(a.pendingAction)
+		a.pendingAction = nil
+	}
+
 	// Background update check
 	updater.CheckAsync(func(result updater.Result) {
 		wailsRuntime.EventsEmit(a.ctx, "update-available", map[string]interface{}{
 			"stable": result.StableUpdate,
 			"beta":   result.BetaUpdate,
 		})
+		for _, update := range []*updater.UpdateInfo{result.StableUpdate, result.BetaUpdate} {
+			if update != nil {
+				events.Publish(events.Event{
+					Type:    events.UpdateAvailable,
+					Version: update.Version,
+					URL:     update.ReleaseURL,
+					Beta:    update.IsBeta,
+				})
+			}
+		}
 	})
 }
 
 func (a *App) shutdown(ctx context.Context) {
+	slog.Info("shutting down")
+	a.cancelOps()
+	if !accounts.WaitIdle(5 * time.Second) {
+		slog.Warn("account operation still running at shutdown")
+	}
+	api.Stop()
 	stopTray()
+	logging.Close()
 }
 
 // ==================== SYSTEM TRAY ====================
@@ -82,75 +183,398 @@ func (a *App) setupSystemTray() {
 		wailsRuntime.Quit(a.ctx)
 	}
 
-	startTray(trayIconData, tooltip, onShow, onQuit)
+	startTray(trayIconData, tooltip, onShow, onQuit, a.trayMenu, a.quickSwitch)
+	go a.watchTrayState()
 }
 
-// ==================== ACCOUNTS ====================
+// trayPollInterval picks up logins and watcher changes that raise no event
+const trayPollInterval = 5 * time.Second
+
+// watchTrayState keeps the tray icon and tooltip in sync with the login,
+// the session watcher, switch results and updates
+func (a *App) watchTrayState() {
+	stream, unsubscribe := events.Subscribe()
+	defer unsubscribe()
+
+	ticker := time.NewTicker(trayPollInterval)
+	defer ticker.Stop()
+
+	a.refreshTray()
+	for {
+		select {
+		case event := <-stream:
+			a.trayMu.Lock()
+			switch event.Type {
+			case events.SwitchStarted, events.SwitchSucceeded, events.SessionCaptured:
+				a.trayFailed = false
+			case events.SwitchFailed, events.WatcherTimeout, events.GameNotStarted:
+				a.trayFailed = true
+			case events.UpdateAvailable:
+				// A stable update wins over a beta
+				if a.trayUpdate == "" || !event.Beta {
+					a.trayUpdate = event.Version
+				}
+			}
+			a.trayMu.Unlock()
+		case <-ticker.C:
+		case <-a.ctx.Done():
+			return
+		}
+		a.refreshTray()
+	}
+}
+
+// refreshTray updates the tray icon and tooltip if they changed
+func (a *App) refreshTray() {
+	a.trayMu.Lock()
+	defer a.trayMu.Unlock()
+
+	state := buildTrayState(readTrayInputs(a.trayFailed, a.trayUpdate))
+	if state == a.trayShown {
+		return
+	}
+
+	if a.trayIcons == nil {
+		a.trayIcons = make(map[trayicon.Status][]byte)
+	}
+	icon, ok := a.trayIcons[state.Status]
+	if !ok {
+		icon, _ = trayicon.Render(trayIconData, state.Status)
+		a.trayIcons[state.Status] = icon
+	}
+
+	if setTrayStatus(icon, state.Tooltip) {
+		a.trayShown = state
+	}
+}
+
+// trayMenu builds the tray menu with the current accounts and login
+func (a *App) trayMenu() []trayMenuItem {
+	accs, _ := accounts.GetAccounts()
+	login, _ := service.CurrentLogin()
+	return buildTrayMenu(accs, login.AccountID)
+}
+
+// quickSwitch switches to an account picked from the tray menu
+func (a *App) quickSwitch(accountID string) {
+	a.runAction(&actions.Action{Kind: actions.KindSwitch, Account: accountID})
+}
+
+// ==================== NOTIFICATIONS ====================
+
+// expiryCheckInterval is how often stored sessions are checked for their age
+const expiryCheckInterval = 6 * time.Hour
 
-// AccountDTO is sent to the frontend (no raw session data exposed)
-type AccountDTO struct {
-	ID              string `json:"id"`
-	Name            string `json:"name"`
-	Email           string `json:"email"`
-	HasSession      bool   `json:"hasSession"`
-	SessionCaptured string `json:"sessionCaptured"`
+// watchNotifications turns bus events into desktop notifications and warns
+// about expiring sessions, once per account and run
+func (a *App) watchNotifications() {
+	stream, unsubscribe := events.Subscribe()
+	defer unsubscribe()
+
+	ticker := time.NewTicker(expiryCheckInterval)
+	defer ticker.Stop()
+
+	warned := make(map[string]bool)
+	checkExpiry := func() {
+		settings := config.GetSettings().Notifications
+		maxAge := time.Duration(settings.ExpiryDays) * 24 * time.Hour
+		expiring, _ := accounts.ExpiringSessions(maxAge, time.Now())
+		for _, acc := range expiring {
+			if warned[acc.ID] {
+				continue
+			}
+			warned[acc.ID] = true
+			age, _ := acc.SessionAge(time.Now())
+			notify.Send(notify.Notification{
+				Category: notify.CategorySessionExpiring,
+				Message: i18n.TF(i18n.NotifyExpiring, map[string]string{
+					"name": acc.Name,
+					"days": strconv.Itoa(int(age.Hours() / 24)),
+				}),
+			})
+		}
+	}
+	checkExpiry()
+
+	for {
+		select {
+		case event := <-stream:
+			// Tray, shortcut, link and API switches report their own result
+			actionFailed := event.Type == events.SwitchFailed && event.Origin != ""
+			if n, ok := notify.FromEvent(event); ok && !actionFailed {
+				notify.Send(n)
+			}
+			// A refreshed session may expire again later
+			if event.Type == events.SessionCaptured || event.Type == events.SwitchSucceeded {
+				delete(warned, event.AccountID)
+			}
+		case <-ticker.C:
+			checkExpiry()
+		case <-a.ctx.Done():
+			return
+		}
+	}
 }
 
-func toDTO(acc accounts.Account) AccountDTO {
-	return AccountDTO{
-		ID:              acc.ID,
-		Name:            acc.Name,
-		Email:           config.MaskEmail(acc.Email),
-		HasSession:      acc.HasSession(),
-		SessionCaptured: acc.SessionCaptured,
+// forwardSwitchProgress emits the switch events of the bus to the frontend
+// as "switch-progress", so it can show each step with its timing
+func (a *App) forwardSwitchProgress() {
+	stream, unsubscribe := events.Subscribe()
+	defer unsubscribe()
+
+	for {
+		select {
+		case event := <-stream:
+			switch event.Type {
+			case events.SwitchStarted, events.SwitchStep, events.SwitchStepDone,
+				events.SwitchSucceeded, events.SwitchFailed:
+				wailsRuntime.EventsEmit(a.ctx, "switch-progress", event)
+			}
+		case <-a.ctx.Done():
+			return
+		}
 	}
 }
 
-// GetAccounts returns all accounts as DTOs
-func (a *App) GetAccounts() ([]AccountDTO, error) {
-	accs, err := accounts.GetAccounts()
+// ==================== ACTIONS ====================
+
+// onSecondInstance runs an action passed to a second instance, e.g. from a
+// desktop shortcut, here in the running instance. A plain second start
+// just brings the window to the front.
+func (a *App) onSecondInstance(args []string) {
+	_, args = config.ParseDataDirFlag(args)
+	action, err := actions.ParseArgs(args)
 	if err != nil {
-		return nil, err
+		notify.Send(notify.Notification{Message: apperror.Message(err), IsError: true})
+		return
+	}
+	if action == nil {
+		wailsRuntime.WindowUnminimise(a.ctx)
+		wailsRuntime.WindowShow(a.ctx)
+		return
+	}
+	go a.runAction(action)
+}
+
+// runAction executes a forwarded action and reports the result as a tray
+// notification and to the frontend
+func (a *App) runAction(action *actions.Action) {
+	switch action.Kind {
+	case actions.KindSwitch:
+		account, err := accounts.FindAccount(action.Account)
+		if err != nil {
+			a.notifyActionResult(i18n.TF(i18n.NotifySwitchFailed, map[string]string{"error": apperror.Message(err)}), false)
+			return
+		}
+
+		ctx := accounts.WithOrigin(a.opCtx, accounts.OriginAction)
+		result := accounts.SwitchAccountGame(ctx, account.ID, action.Game)
+		if result.NeedsConfirmation {
+			if ok, _ := a.ConfirmCooldown(result.Error); !ok {
+				return
+			}
+			result = accounts.ConfirmSwitchAccountGame(ctx, account.ID, action.Game)
+		}
+
+		a.notifySwitchResult(service.ToSwitchResultDTO(result))
 	}
-	dtos := make([]AccountDTO, len(accs))
-	for i, acc := range accs {
-		dtos[i] = toDTO(acc)
+}
+
+// notifySwitchResult reports a switch that was not started from the window,
+// whatever the notification settings say. watchNotifications skips the
+// switch.failed events of these switches so a failure is shown once.
+func (a *App) notifySwitchResult(result service.SwitchResultDTO) {
+	if !result.Success {
+		a.notifyActionResult(i18n.TF(i18n.NotifySwitchFailed, map[string]string{"error": result.Error}), false)
+		return
 	}
-	return dtos, nil
+	a.notifyActionResult(i18n.TF(i18n.NotifySwitched, map[string]string{
+		"name":    result.AccountName,
+		"message": result.Message,
+	}), true)
+}
+
+// notifyActionResult shows the outcome of an action on the desktop and in the window
+func (a *App) notifyActionResult(message string, success bool) {
+	notify.Send(notify.Notification{Message: message, IsError: !success})
+	a.emitActionResult(message, success)
 }
 
-// SwitchResultDTO is the result of a switch operation
-type SwitchResultDTO struct {
-	Success     bool   `json:"success"`
-	AccountName string `json:"accountName"`
-	Email       string `json:"email"`
-	HasSession  bool   `json:"hasSession"`
-	Message     string `json:"message"`
-	Error       string `json:"error"`
+// emitActionResult shows the outcome of an action in the window
+func (a *App) emitActionResult(message string, success bool) {
+	wailsRuntime.EventsEmit(a.ctx, "action-result", map[string]interface{}{
+		"success": success,
+		"message": message,
+	})
+}
+
+// ==================== ACCOUNTS ====================
+
+// GetAccounts returns all accounts as DTOs
+func (a *App) GetAccounts() ([]service.AccountDTO, error) {
+	dtos, err := service.ListAccounts()
+	return dtos, apperror.Localize(err)
 }
 
 // SwitchAccount switches to the given account
-func (a *App) SwitchAccount(id string) SwitchResultDTO {
-	result := accounts.SwitchAccount(id)
-	return SwitchResultDTO{
-		Success:     result.Success,
-		AccountName: result.AccountName,
-		Email:       config.MaskEmail(result.Email),
-		HasSession:  result.HasSession,
-		Message:     result.Message,
-		Error:       result.Error,
+func (a *App) SwitchAccount(id string) service.SwitchResultDTO {
+	return service.Switch(a.opCtx, id, "", false)
+}
+
+// SwitchAccountGame switches to the given account with game ("eft" or "arena") selected in the launcher
+func (a *App) SwitchAccountGame(id, game string) service.SwitchResultDTO {
+	return service.Switch(a.opCtx, id, game, false)
+}
+
+// ConfirmSwitchAccountGame switches after the user confirmed switching despite the cooldown
+func (a *App) ConfirmSwitchAccountGame(id, game string) service.SwitchResultDTO {
+	return service.Switch(a.opCtx, id, game, true)
+}
+
+// GetWatcherStatus tells whether the session watcher waits for a login
+func (a *App) GetWatcherStatus() service.WatcherDTO {
+	return service.WatcherStatus()
+}
+
+// GetCurrentLogin returns the account currently logged in to the launcher
+func (a *App) GetCurrentLogin() (service.LoginDTO, error) {
+	return service.CurrentLogin()
+}
+
+// CreateAccountShortcut puts a desktop shortcut that switches to the account
+// on the desktop and returns its path
+func (a *App) CreateAccountShortcut(id string) (string, error) {
+	account, err := accounts.GetAccountByID(id)
+	if err != nil {
+		return "", apperror.Localize(err)
+	}
+	if account == nil {
+		return "", apperror.Localize(apperror.New(apperror.AccountNotFound, nil))
 	}
+	return config.CreateShortcut("Tarkov - "+account.Name, []string{"--switch", account.ID})
+}
+
+// GetAccountSwitchURI returns the tarkovswitch:// link for an account
+func (a *App) GetAccountSwitchURI(id string) string {
+	return actions.SwitchURI(id)
+}
+
+// SetAccountDefaultGame sets the game selected in the launcher for an account ("" keeps the captured one)
+func (a *App) SetAccountDefaultGame(id, game string) error {
+	return apperror.Localize(accounts.SetAccountDefaultGame(id, game))
+}
+
+// SetAccountGameProfile enables or disables per-account EFT settings for an account
+func (a *App) SetAccountGameProfile(id string, enabled bool) error {
+	return apperror.Localize(accounts.SetAccountGameProfile(id, enabled))
+}
+
+// SetAccountCefProfile enables or disables the launcher cookie/local storage snapshot for an account
+func (a *App) SetAccountCefProfile(id string, enabled bool) error {
+	return apperror.Localize(accounts.SetAccountCefProfile(id, enabled))
 }
 
 // AddAccount adds a new account and starts the login flow
 func (a *App) AddAccount(name, email string) error {
-	_, err := accounts.AddAccount(name, email)
-	return err
+	_, err := accounts.AddAccount(a.opCtx, name, email)
+	return apperror.Localize(err)
 }
 
 // DeleteAccount removes an account by ID
 func (a *App) DeleteAccount(id string) error {
-	return accounts.DeleteAccount(id)
+	return apperror.Localize(accounts.DeleteAccount(a.opCtx, id))
+}
+
+// ==================== CACHE ====================
+
+// PreviewCacheClear reports what a switch to the given account would delete.
+// An empty ID previews the global target list.
+func (a *App) PreviewCacheClear(id string) (service.CacheReportDTO, error) {
+	targets := config.GetSettings().CacheTargets
+	if id != "" {
+		account, err := accounts.GetAccountByID(id)
+		if err != nil {
+			return service.CacheReportDTO{}, apperror.Localize(err)
+		}
+		if account != nil {
+			targets = account.EffectiveCacheTargets()
+		}
+	}
+	return service.ToCacheReportDTO(launcher.ClearGameCache(targets, true)), nil
+}
+
+// ClearTempFolder empties the launcher temp folder without switching
+func (a *App) ClearTempFolder() service.CacheReportDTO {
+	return service.ToCacheReportDTO(launcher.CleanTempFolder(false))
+}
+
+// SetCacheTargets saves the global cache target list (nil restores the defaults)
+func (a *App) SetCacheTargets(targets []string) error {
+	return apperror.Localize(config.SetCacheTargets(targets))
+}
+
+// SetAccountCacheTargets overrides the cache targets for one account
+func (a *App) SetAccountCacheTargets(id string, targets []string) error {
+	if targets == nil {
+		targets = []string{}
+	}
+	return apperror.Localize(accounts.SetAccountCacheTargets(id, targets))
+}
+
+// ResetAccountCacheTargets makes an account use the global cache targets again
+func (a *App) ResetAccountCacheTargets(id string) error {
+	return apperror.Localize(accounts.SetAccountCacheTargets(id, nil))
+}
+
+// ==================== SESSION SCHEMA ====================
+
+// SessionFieldDTO is one entry of the session capture/restore schema
+type SessionFieldDTO struct {
+	Key      string      `json:"key"`
+	Target   string      `json:"target"`
+	Field    string      `json:"field"`
+	Fixed    interface{} `json:"fixed"`
+	Disabled bool        `json:"disabled"`
+}
+
+// GetSessionSchema returns the effective session schema (defaults merged with overrides)
+func (a *App) GetSessionSchema() []SessionFieldDTO {
+	schema := config.SessionSchema()
+	dtos := make([]SessionFieldDTO, len(schema))
+	for i, f := range schema {
+		dtos[i] = SessionFieldDTO{
+			Key:    f.Key,
+			Target: f.TargetOrDefault(),
+			Field:  f.TargetField(),
+			Fixed:  f.Fixed,
+		}
+	}
+	return dtos
+}
+
+// SetSessionFields saves the user overrides for the session schema
+func (a *App) SetSessionFields(fields []SessionFieldDTO) error {
+	overrides := make([]config.SessionField, len(fields))
+	for i, f := range fields {
+		overrides[i] = config.SessionField{
+			Key:      f.Key,
+			Target:   f.Target,
+			Field:    f.Field,
+			Fixed:    f.Fixed,
+			Disabled: f.Disabled,
+		}
+	}
+	return config.SetSessionFields(overrides)
+}
+
+// FindSessionFiles lists the launcher files that currently hold auth state
+func (a *App) FindSessionFiles() ([]string, error) {
+	return launcher.DiscoverSessionFiles()
+}
+
+// SetSessionFilePatterns saves which launcher files may hold auth state (nil restores the defaults)
+func (a *App) SetSessionFilePatterns(patterns []string) error {
+	return config.SetSessionFilePatterns(patterns)
 }
 
 // ==================== SETTINGS ====================
@@ -162,17 +586,132 @@ type SettingsDTO struct {
 	StreamerMode bool   `json:"streamerMode"`
 	Theme        string `json:"theme"`
 	AutoStart    bool   `json:"autoStart"`
+	URIScheme    bool   `json:"uriScheme"`
+
+	VerifySeconds        int      `json:"verifySeconds"`
+	CacheTargets         []string `json:"cacheTargets"`
+	LaunchGameAfterLogin bool     `json:"launchGameAfterLogin"`
+	ProfileInclude       []string `json:"profileInclude"`
+	ProfileExclude       []string `json:"profileExclude"`
+	SessionFilePatterns  []string `json:"sessionFilePatterns"`
+
+	IsWine bool    `json:"isWine"`
+	Wine   WineDTO `json:"wine"`
+
+	DataDir       string `json:"dataDir"`
+	DataDirSource string `json:"dataDirSource"`
+
+	Hooks []HookDTO `json:"hooks"`
+
+	SwitchPolicy SwitchPolicyDTO `json:"switchPolicy"`
+
+	API APIDTO `json:"api"`
+
+	Notifications NotificationsDTO `json:"notifications"`
+}
+
+// NotificationsDTO toggles desktop notifications per category
+type NotificationsDTO struct {
+	SessionCaptured bool `json:"sessionCaptured"`
+	CaptureTimeout  bool `json:"captureTimeout"`
+	SwitchFailed    bool `json:"switchFailed"`
+	SessionExpiring bool `json:"sessionExpiring"`
+	ExpiryDays      int  `json:"expiryDays"`
+}
+
+// APIDTO holds the local HTTP API settings
+type APIDTO struct {
+	Enabled bool   `json:"enabled"`
+	Port    int    `json:"port"`
+	Running bool   `json:"running"`
+	Error   string `json:"error"` // why an enabled API is not running
+}
+
+// SwitchPolicyDTO limits how often an account is switched into
+type SwitchPolicyDTO struct {
+	Mode          string `json:"mode"` // off, warn or confirm
+	MaxSwitches   int    `json:"maxSwitches"`
+	WindowMinutes int    `json:"windowMinutes"`
+}
+
+// WineDTO holds the Wine launcher backend settings (Linux only)
+type WineDTO struct {
+	Prefix  string            `json:"prefix"`
+	Command []string          `json:"command"`
+	User    string            `json:"user"`
+	Env     map[string]string `json:"env"`
+}
+
+// HookDTO is a user command run on a switch event
+type HookDTO struct {
+	Event          string   `json:"event"`
+	Command        []string `json:"command"`
+	TimeoutSeconds int      `json:"timeoutSeconds"`
+	Veto           bool     `json:"veto"`
 }
 
 // GetSettings returns current settings
 func (a *App) GetSettings() SettingsDTO {
 	s := config.GetSettings()
+
+	hooks := make([]HookDTO, len(s.Hooks))
+	for i, h := range s.Hooks {
+		hooks[i] = HookDTO{
+			Event:          h.Event,
+			Command:        h.Command,
+			TimeoutSeconds: h.Timeout,
+			Veto:           h.Veto,
+		}
+	}
+
 	return SettingsDTO{
 		LauncherPath: s.LauncherPath,
 		Language:     i18n.GetLanguage(),
 		StreamerMode: s.StreamerMode,
 		Theme:        s.Theme,
 		AutoStart:    s.AutoStart,
+		URIScheme:    s.URIScheme,
+
+		VerifySeconds:        s.VerifySeconds,
+		CacheTargets:         s.CacheTargets,
+		LaunchGameAfterLogin: s.LaunchGameAfterLogin,
+		ProfileInclude:       s.ProfileInclude,
+		ProfileExclude:       s.ProfileExclude,
+		SessionFilePatterns:  s.SessionFilePatterns,
+
+		IsWine: config.IsWine(),
+		Wine: WineDTO{
+			Prefix:  s.Wine.Prefix,
+			Command: s.Wine.Command,
+			User:    s.Wine.User,
+			Env:     s.Wine.Env,
+		},
+
+		DataDir:       config.GetPaths().DataDir,
+		DataDirSource: config.GetPaths().DataDirSource,
+
+		Hooks: hooks,
+
+		SwitchPolicy: SwitchPolicyDTO{
+			Mode:          s.SwitchPolicy.Mode,
+			MaxSwitches:   s.SwitchPolicy.MaxSwitches,
+			WindowMinutes: s.SwitchPolicy.WindowMinutes,
+		},
+
+		API: APIDTO{
+			Enabled: s.API.Enabled,
+			Port:    s.API.Port,
+			Running: api.IsRunning(),
+			Error:   apiError(),
+		},
+
+		Notifications: NotificationsDTO{
+			SessionCaptured: s.Notifications.SessionCaptured,
+			CaptureTimeout:  s.Notifications.CaptureTimeout,
+			SwitchFailed:    s.Notifications.SwitchFailed,
+			SessionExpiring: s.Notifications.SessionExpiring,
+			ExpiryDays:      s.Notifications.ExpiryDays,
+		},
 	}
 }
 
@@ -204,6 +743,114 @@ func (a *App) SetAutoStart(enabled bool) error {
 	return config.SetAutoStart(enabled)
 }
 
+// SetURIScheme registers or removes the tarkovswitch:// link handler
+func (a *App) SetURIScheme(enabled bool) error {
+	if err := config.ApplyURIScheme(enabled); err != nil {
+		return err
+	}
+	return config.SetURIScheme(enabled)
+}
+
+// SetVerifySeconds sets how long a switch is verified after the launcher starts
+func (a *App) SetVerifySeconds(seconds int) error {
+	return config.SetVerifySeconds(seconds)
+}
+
+// SetLaunchGameAfterLogin toggles starting the game automatically after a verified login.
+// It cannot be enabled before gameLaunchArgs has arguments for EFT or Arena.
+func (a *App) SetLaunchGameAfterLogin(enabled bool) error {
+	if enabled && !config.HasGameLaunchArgs(launcher.GameEFT) && !config.HasGameLaunchArgs(launcher.GameArena) {
+		return apperror.Localize(apperror.New(apperror.NoLaunchArgs, nil, "game", "EFT/Arena"))
+	}
+	return config.SetLaunchGameAfterLogin(enabled)
+}
+
+// SetProfileFilter saves which EFT settings files are kept per account
+func (a *App) SetProfileFilter(include, exclude []string) error {
+	return config.SetProfileFilter(include, exclude)
+}
+
+// SetWineSettings saves the Wine prefix and launcher command line (Linux only)
+func (a *App) SetWineSettings(wine WineDTO) error {
+	return config.SetWineSettings(config.WineSettings{
+		Prefix:  wine.Prefix,
+		Command: wine.Command,
+		User:    wine.User,
+		Env:     wine.Env,
+	})
+}
+
+// SetHooks replaces the user hook commands
+func (a *App) SetHooks(hooks []HookDTO) error {
+	list := make([]config.Hook, len(hooks))
+	for i, h := range hooks {
+		list[i] = config.Hook{
+			Event:   h.Event,
+			Command: h.Command,
+			Timeout: h.TimeoutSeconds,
+			Veto:    h.Veto,
+		}
+	}
+	return config.SetHooks(list)
+}
+
+// SetSwitchPolicy sets the switch cooldown policy
+func (a *App) SetSwitchPolicy(policy SwitchPolicyDTO) error {
+	return config.SetSwitchPolicy(config.SwitchPolicy{
+		Mode:          policy.Mode,
+		MaxSwitches:   policy.MaxSwitches,
+		WindowMinutes: policy.WindowMinutes,
+	})
+}
+
+// apiError describes why the API failed to start, "" if it did not fail
+func apiError() string {
+	if err := api.LastError(); err != nil {
+		return i18n.TF(i18n.APIStartFailed, map[string]string{"error": err.Error()})
+	}
+	return ""
+}
+
+// SetAPISettings saves the HTTP API settings and starts or stops the API
+func (a *App) SetAPISettings(settings APIDTO) error {
+	if err := config.SetAPISettings(config.APISettings{
+		Enabled: settings.Enabled,
+		Port:    settings.Port,
+	}); err != nil {
+		return err
+	}
+	if !settings.Enabled {
+		api.Stop()
+		return nil
+	}
+	if err := api.Start(a.opCtx); err != nil {
+		slog.Error("API not started", "err", err)
+		return errors.New(apiError())
+	}
+	return nil
+}
+
+// SetNotifications saves which desktop notifications are shown
+func (a *App) SetNotifications(notifications NotificationsDTO) error {
+	return config.SetNotificationSettings(config.NotificationSettings{
+		SessionCaptured: notifications.SessionCaptured,
+		CaptureTimeout:  notifications.CaptureTimeout,
+		SwitchFailed:    notifications.SwitchFailed,
+		SessionExpiring: notifications.SessionExpiring,
+		ExpiryDays:      notifications.ExpiryDays,
+	})
+}
+
+// GetAPIToken returns the bearer token for the HTTP API
+func (a *App) GetAPIToken() (string, error) {
+	return api.Token()
+}
+
+// RegenerateAPIToken replaces the HTTP API token
+func (a *App) RegenerateAPIToken() (string, error) {
+	return api.RegenerateToken()
+}
+
 // SetTheme saves the theme preference
 func (a *App) SetTheme(id string) error {
 	return config.SetTheme(id)
@@ -219,6 +866,80 @@ func (a *App) BrowseLauncherPath() (string, error) {
 	})
 }
 
+// ==================== DIAGNOSTICS ====================
+
+// doctorTitles maps doctor check IDs to their translation keys
+var doctorTitles = map[string]string{
+	doctor.CheckDataDir:          i18n.DoctorDataDir,
+	doctor.CheckSettings:         i18n.DoctorSettings,
+	doctor.CheckWine:             i18n.DoctorWine,
+	doctor.CheckLauncherPath:     i18n.DoctorLauncherPath,
+	doctor.CheckLauncherSettings: i18n.DoctorLauncherSettings,
+	doctor.CheckLauncherWritable: i18n.DoctorLauncherWritable,
+	doctor.CheckLauncherRunning:  i18n.DoctorLauncherRunning,
+	doctor.CheckGameIni:          i18n.DoctorGameIni,
+	doctor.CheckKey:              i18n.DoctorKey,
+	doctor.CheckAccounts:         i18n.DoctorAccounts,
+	doctor.CheckSessions:         i18n.DoctorSessions,
+}
+
+// DiagnosticCheckDTO is one doctor check for the frontend
+type DiagnosticCheckDTO struct {
+	ID     string `json:"id"`
+	Title  string `json:"title"`
+	Status string `json:"status"` // ok, warn, fail
+	Detail string `json:"detail"`
+}
+
+// DiagnosticsDTO is the doctor report for the frontend
+type DiagnosticsDTO struct {
+	Version string               `json:"version"`
+	OS      string               `json:"os"`
+	Failed  bool                 `json:"failed"`
+	Checks  []DiagnosticCheckDTO `json:"checks"`
+}
+
+// RunDiagnostics runs the doctor checks
+func (a *App) RunDiagnostics() DiagnosticsDTO {
+	report := doctor.Run()
+	dto := DiagnosticsDTO{
+		Version: report.Version,
+		OS:      report.OS,
+		Failed:  report.Failed(),
+		Checks:  make([]DiagnosticCheckDTO, len(report.Checks)),
+	}
+	for i, c := range report.Checks {
+		dto.Checks[i] = DiagnosticCheckDTO{
+			ID:     c.ID,
+			Title:  i18n.T(doctorTitles[c.ID]),
+			Status: string(c.Status),
+			Detail: c.Detail,
+		}
+	}
+	return dto
+}
+
+// SaveSupportBundle runs the doctor checks and saves them with the redacted
+// logs as a zip. Returns the path, or "" when the dialog was cancelled.
+func (a *App) SaveSupportBundle() (string, error) {
+	path, err := wailsRuntime.SaveFileDialog(a.ctx, wailsRuntime.SaveDialogOptions{
+		Title:           i18n.T(i18n.BtnSupportBundle),
+		DefaultFilename: "tarkov-switcher-support-" + time.Now().Format("20060102-150405") + ".zip",
+		Filters: []wailsRuntime.FileFilter{
+			{DisplayName: "Zip (*.zip)", Pattern: "*.zip"},
+		},
+	})
+	if err != nil || path == "" {
+		return "", err
+	}
+	if err := doctor.WriteBundle(path, doctor.Run()); err != nil {
+		slog.Error("support bundle not written", "path", path, "err", err)
+		return "", err
+	}
+	slog.Info("support bundle written", "path", path)
+	return path, nil
+}
+
 // ==================== TRANSLATIONS ====================
 
 // GetAllTranslations returns all translations for the current language
@@ -233,8 +954,17 @@ func (a *App) GetAllTranslations() map[string]string {
 		i18n.SettingsTitle, i18n.LabelLanguage, i18n.LabelLauncherPath,
 		i18n.PlaceholderLauncher, i18n.BtnBrowse, i18n.BtnSave,
 		i18n.LabelAutoStart, i18n.AutoStartHelp, i18n.BtnQuit,
+		i18n.BtnShortcut, i18n.StatusShortcutCreated, i18n.LabelURIScheme, i18n.URISchemeHelp,
+		i18n.LabelAPI, i18n.APIHelp, i18n.BtnCopyToken, i18n.StatusTokenCopied,
+		i18n.LabelNotifications, i18n.NotifyOptCaptured, i18n.NotifyOptTimeout,
+		i18n.NotifyOptFailed, i18n.NotifyOptExpiring,
+		i18n.LabelDiagnostics, i18n.DiagnosticsHelp, i18n.BtnRunDiagnostics,
+		i18n.BtnSupportBundle, i18n.StatusBundleSaved,
+		i18n.StepSaveSession, i18n.StepKillLauncher, i18n.StepClearCache,
+		i18n.StepRestoreProfiles, i18n.StepRestoreSession, i18n.StepResetSession,
+		i18n.StepStartLauncher, i18n.StepTotal,
 		i18n.LabelStreamerMode, i18n.StreamerModeHelp,
-		i18n.LabelTheme,
+		i18n.LabelTheme, i18n.LabelDataDir, i18n.StatusCooldown,
 		i18n.StatusFillFields, i18n.StatusAccountAdded, i18n.StatusAccountDeleted,
 		i18n.StatusLauncherRestart, i18n.StatusAutoLoginActive, i18n.StatusManualLogin,
 		i18n.StatusPathSaved, i18n.StatusEnterPath, i18n.StatusLanguageSaved,
@@ -279,3 +1009,19 @@ func (a *App) ConfirmDelete() (bool, error) {
 	}
 	return result == "Yes", nil
 }
+
+// ConfirmCooldown asks whether to switch despite the cooldown; message is
+// the error of the switch result that needs confirmation
+func (a *App) ConfirmCooldown(message string) (bool, error) {
+	result, err := wailsRuntime.MessageDialog(a.ctx, wailsRuntime.MessageDialogOptions{
+		Type:          wailsRuntime.QuestionDialog,
+		Title:         i18n.T(i18n.BtnSwitch),
+		Message:       message,
+		DefaultButton: "No",
+		Buttons:       []string{"Yes", "No"},
+	})
+	if err != nil {
+		return false, err
+	}
+	return result == "Yes", nil
+}
@@ -7,6 +7,9 @@
 // Translation cache — loaded from Go on startup and language change
 let T = {};
 
+// Desktop notification toggles, saved as a whole on every change
+let notificationSettings = {};
+
 // ======================== INITIALIZATION ========================
 
 document.addEventListener('DOMContentLoaded', async () => {
@@ -130,6 +133,19 @@ async function applyTranslations() {
     setText('settings-autostart-help', t('autoStartHelp'));
     setText('settings-streamer-label', t('labelStreamerMode'));
     setText('settings-streamer-help', t('streamerModeHelp'));
+    setText('settings-uri-label', t('labelUriScheme'));
+    setText('settings-uri-help', t('uriSchemeHelp'));
+    setText('settings-api-label', t('labelApi'));
+    setText('settings-api-token-btn', t('btnCopyToken'));
+    setText('settings-notify-label', t('labelNotifications'));
+    setText('settings-notify-captured-label', t('notifyOptCaptured'));
+    setText('settings-notify-timeout-label', t('notifyOptTimeout'));
+    setText('settings-notify-failed-label', t('notifyOptFailed'));
+    setText('settings-notify-expiring-label', t('notifyOptExpiring'));
+    setText('settings-doctor-label', t('labelDiagnostics'));
+    setText('settings-doctor-help', t('diagnosticsHelp'));
+    setText('settings-doctor-btn', t('btnRunDiagnostics'));
+    setText('settings-bundle-btn', t('btnSupportBundle'));
     setText('settings-quit-btn', t('btnQuit'));
 
     // Version
@@ -221,15 +237,26 @@ function createAccountCard(acc) {
                 '<span class="status-dot"></span>' +
                 '<span>' + escapeHtml(statusText) + '</span>' +
             '</div>' +
+            '<div class="account-cooldown hidden"></div>' +
         '</div>' +
         '<div class="account-actions">' +
             '<button class="btn btn-primary" data-action="switch">' + t('btnSwitch') + '</button>' +
+            '<button class="btn btn-secondary" data-action="shortcut">' + t('btnShortcut') + '</button>' +
             '<button class="btn btn-danger" data-action="delete">' + t('btnDelete') + '</button>' +
         '</div>';
 
+    if (acc.cooldownSeconds > 0) {
+        const cooldownEl = card.querySelector('.account-cooldown');
+        cooldownEl.dataset.until = Date.now() + acc.cooldownSeconds * 1000;
+        updateCooldown(cooldownEl);
+    }
+
     card.querySelector('[data-action="switch"]').addEventListener('click', () => {
         onSwitchAccount(acc.id);
     });
+    card.querySelector('[data-action="shortcut"]').addEventListener('click', () => {
+        onCreateShortcut(acc.id);
+    });
     card.querySelector('[data-action="delete"]').addEventListener('click', () => {
         onDeleteAccount(acc.id);
     });
@@ -237,13 +264,43 @@ function createAccountCard(acc) {
     return card;
 }
 
+// updateCooldown shows the time left until switching into an account is
+// within the switch policy again, and hides the badge once it has passed
+function updateCooldown(el) {
+    const left = Math.ceil((Number(el.dataset.until) - Date.now()) / 1000);
+    if (left <= 0) {
+        el.classList.add('hidden');
+        delete el.dataset.until;
+        return;
+    }
+    const minutes = Math.floor(left / 60);
+    const seconds = String(left % 60).padStart(2, '0');
+    el.textContent = '\u23F3 ' + tf('statusCooldown', { time: minutes + ':' + seconds });
+    el.classList.remove('hidden');
+}
+
+setInterval(() => {
+    document.querySelectorAll('.account-cooldown[data-until]').forEach(updateCooldown);
+}, 1000);
+
 async function onSwitchAccount(id) {
     const statusEl = document.getElementById('accounts-status');
     statusEl.textContent = '\u23F3 ' + t('statusLauncherRestarting');
     statusEl.className = 'status-message info';
 
     try {
-        const result = await window.go.main.App.SwitchAccount(id);
+        let result = await window.go.main.App.SwitchAccount(id);
+
+        // Switch policy wants a confirmation for this account
+        if (!result.success && result.needsConfirmation) {
+            const confirmed = await window.go.main.App.ConfirmCooldown(result.error);
+            if (!confirmed) {
+                statusEl.textContent = '';
+                statusEl.className = 'status-message';
+                return;
+            }
+            result = await window.go.main.App.ConfirmSwitchAccountGame(id, '');
+        }
 
         if (result.success) {
             if (result.hasSession) {
@@ -258,6 +315,11 @@ async function onSwitchAccount(id) {
                 });
                 statusEl.className = 'status-message warning';
             }
+            if (result.warnings && result.warnings.length > 0) {
+                statusEl.textContent += ' \u26A0\uFE0F ' + result.warnings.join(' ');
+                statusEl.className = 'status-message warning';
+            }
+            await loadAccountsTab();
         } else {
             statusEl.textContent = '\u274C ' + result.error;
             statusEl.className = 'status-message error';
@@ -268,6 +330,73 @@ async function onSwitchAccount(id) {
     }
 }
 
+const STEP_KEYS = {
+    'save-session': 'stepSaveSession',
+    'kill-launcher': 'stepKillLauncher',
+    'clear-cache': 'stepClearCache',
+    'restore-profiles': 'stepRestoreProfiles',
+    'restore-session': 'stepRestoreSession',
+    'reset-session': 'stepResetSession',
+    'start-launcher': 'stepStartLauncher'
+};
+
+function onSwitchProgress(event) {
+    const list = document.getElementById('switch-steps');
+    if (!event || !list) return;
+
+    switch (event.type) {
+        case 'switch.started':
+            list.innerHTML = '';
+            list.classList.remove('hidden');
+            break;
+        case 'switch.step': {
+            const item = document.createElement('li');
+            item.className = 'switch-step running';
+            item.dataset.step = event.step;
+            item.innerHTML = '<span class="step-icon">\u23F3</span>' +
+                '<span class="step-name">' + escapeHtml(t(STEP_KEYS[event.step] || event.step)) + '</span>' +
+                '<span class="step-time"></span>';
+            list.appendChild(item);
+            break;
+        }
+        case 'switch.step.done': {
+            const item = list.querySelector('.switch-step.running[data-step="' + event.step + '"]');
+            if (!item) return;
+            const failed = !!event.error;
+            item.className = 'switch-step ' + (failed ? 'failed' : 'done');
+            item.querySelector('.step-icon').textContent = failed ? '\u274C' : '\u2713';
+            item.querySelector('.step-time').textContent = formatDuration(event.durationMs || 0);
+            break;
+        }
+        case 'switch.succeeded':
+        case 'switch.failed': {
+            const item = document.createElement('li');
+            item.className = 'switch-step total';
+            item.innerHTML = '<span class="step-icon"></span>' +
+                '<span class="step-name">' + escapeHtml(t('stepTotal')) + '</span>' +
+                '<span class="step-time">' + formatDuration(event.durationMs || 0) + '</span>';
+            list.appendChild(item);
+            break;
+        }
+    }
+}
+
+function formatDuration(ms) {
+    return ms < 1000 ? ms + ' ms' : (ms / 1000).toFixed(1) + ' s';
+}
+
+async function onCreateShortcut(id) {
+    const statusEl = document.getElementById('accounts-status');
+    try {
+        const path = await window.go.main.App.CreateAccountShortcut(id);
+        statusEl.textContent = '\u2713 ' + tf('statusShortcutCreated', { path: path });
+        statusEl.className = 'status-message success';
+    } catch (e) {
+        statusEl.textContent = '\u274C ' + e;
+        statusEl.className = 'status-message error';
+    }
+}
+
 async function onDeleteAccount(id) {
     try {
         const confirmed = await window.go.main.App.ConfirmDelete();
@@ -295,6 +424,14 @@ function setupEventListeners() {
     document.getElementById('settings-save-btn').addEventListener('click', onSavePath);
     document.getElementById('settings-autostart-check').addEventListener('change', onAutoStartToggle);
     document.getElementById('settings-streamer-check').addEventListener('change', onStreamerToggle);
+    document.getElementById('settings-uri-check').addEventListener('change', onURISchemeToggle);
+    document.getElementById('settings-api-check').addEventListener('change', onAPIToggle);
+    document.getElementById('settings-api-token-btn').addEventListener('click', onCopyAPIToken);
+    document.querySelectorAll('.notify-check').forEach(check => {
+        check.addEventListener('change', onNotificationsChange);
+    });
+    document.getElementById('settings-doctor-btn').addEventListener('click', onRunDiagnostics);
+    document.getElementById('settings-bundle-btn').addEventListener('click', onSaveSupportBundle);
     document.getElementById('settings-quit-btn').addEventListener('click', onQuitApp);
 
     // Allow Enter key in add form
@@ -351,6 +488,15 @@ async function loadSettingsValues() {
         document.getElementById('settings-path-input').value = settings.launcherPath;
         document.getElementById('settings-autostart-check').checked = settings.autoStart;
         document.getElementById('settings-streamer-check').checked = settings.streamerMode;
+        document.getElementById('settings-uri-check').checked = settings.uriScheme;
+        document.getElementById('settings-api-check').checked = settings.api.enabled;
+        document.getElementById('settings-api-check').dataset.port = settings.api.port;
+        setText('settings-api-help', settings.api.error || tf('apiHelp', { port: settings.api.port }));
+        document.getElementById('settings-api-help').classList.toggle('error-text', !!settings.api.error);
+        notificationSettings = settings.notifications;
+        document.querySelectorAll('.notify-check').forEach(check => {
+            check.checked = settings.notifications[check.dataset.key];
+        });
 
         // Set theme dropdown
         const themeSelect = document.getElementById('settings-theme-select');
@@ -359,6 +505,10 @@ async function loadSettingsValues() {
         } else {
             themeSelect.value = 'eft';
         }
+
+        // Show where data lives and why (flag, env, portable, xdg, appdata)
+        document.getElementById('data-dir-text').textContent =
+            t('labelDataDir') + ': ' + settings.dataDir + ' (' + settings.dataDirSource + ')';
     } catch (e) {
         console.error('Failed to load settings:', e);
     }
@@ -412,6 +562,70 @@ async function onSavePath() {
     }
 }
 
+async function onURISchemeToggle() {
+    const checked = document.getElementById('settings-uri-check').checked;
+    const statusEl = document.getElementById('settings-status');
+
+    try {
+        await window.go.main.App.SetURIScheme(checked);
+        statusEl.textContent = '\u2713 tarkovswitch:// ' + (checked ? 'ON' : 'OFF');
+        statusEl.className = 'status-message success';
+    } catch (e) {
+        statusEl.textContent = '\u274C ' + e;
+        statusEl.className = 'status-message error';
+        document.getElementById('settings-uri-check').checked = !checked;
+    }
+}
+
+async function onAPIToggle() {
+    const check = document.getElementById('settings-api-check');
+    const statusEl = document.getElementById('settings-status');
+
+    try {
+        await window.go.main.App.SetAPISettings({
+            enabled: check.checked,
+            port: Number(check.dataset.port) || 0
+        });
+        statusEl.textContent = '\u2713 API ' + (check.checked ? 'ON' : 'OFF');
+        statusEl.className = 'status-message success';
+    } catch (e) {
+        statusEl.textContent = '\u274C ' + e;
+        statusEl.className = 'status-message error';
+        check.checked = !check.checked;
+    }
+}
+
+async function onNotificationsChange(e) {
+    const check = e.target;
+    const statusEl = document.getElementById('settings-status');
+    const updated = { ...notificationSettings, [check.dataset.key]: check.checked };
+
+    try {
+        await window.go.main.App.SetNotifications(updated);
+        notificationSettings = updated;
+        statusEl.textContent = '\u2713 ' + t('labelNotifications');
+        statusEl.className = 'status-message success';
+    } catch (e) {
+        statusEl.textContent = '\u274C ' + e;
+        statusEl.className = 'status-message error';
+        check.checked = !check.checked;
+    }
+}
+
+async function onCopyAPIToken() {
+    const statusEl = document.getElementById('settings-status');
+
+    try {
+        const token = await window.go.main.App.GetAPIToken();
+        await window.runtime.ClipboardSetText(token);
+        statusEl.textContent = '\u2713 ' + t('statusTokenCopied');
+        statusEl.className = 'status-message success';
+    } catch (e) {
+        statusEl.textContent = '\u274C ' + e;
+        statusEl.className = 'status-message error';
+    }
+}
+
 async function onAutoStartToggle() {
     const checked = document.getElementById('settings-autostart-check').checked;
     const statusEl = document.getElementById('settings-status');
@@ -442,6 +656,43 @@ async function onStreamerToggle() {
     }
 }
 
+// ======================== DIAGNOSTICS ========================
+
+const DOCTOR_ICONS = { ok: '\u2713', warn: '\u26A0', fail: '\u274C' };
+
+async function onRunDiagnostics() {
+    const list = document.getElementById('settings-doctor-list');
+
+    try {
+        const report = await window.go.main.App.RunDiagnostics();
+        list.innerHTML = report.checks.map(check =>
+            '<li class="doctor-check ' + check.status + '">' +
+                '<span class="doctor-icon">' + DOCTOR_ICONS[check.status] + '</span>' +
+                '<span class="doctor-title">' + escapeHtml(check.title) + '</span>' +
+                '<span class="doctor-detail">' + escapeHtml(check.detail) + '</span>' +
+            '</li>'
+        ).join('');
+    } catch (e) {
+        const statusEl = document.getElementById('settings-status');
+        statusEl.textContent = '\u274C ' + e;
+        statusEl.className = 'status-message error';
+    }
+}
+
+async function onSaveSupportBundle() {
+    const statusEl = document.getElementById('settings-status');
+
+    try {
+        const path = await window.go.main.App.SaveSupportBundle();
+        if (!path) return; // dialog cancelled
+        statusEl.textContent = '\u2713 ' + tf('statusBundleSaved', { path });
+        statusEl.className = 'status-message success';
+    } catch (e) {
+        statusEl.textContent = '\u274C ' + e;
+        statusEl.className = 'status-message error';
+    }
+}
+
 // ======================== QUIT ========================
 
 async function onQuitApp() {
@@ -465,6 +716,29 @@ function setupWailsEvents() {
         await loadAccountsTab();
     });
 
+    // Post-switch verification finished -> show result, refresh session status
+    window.runtime.EventsOn('session-verified', async (data) => {
+        const statusEl = document.getElementById('accounts-status');
+        if (data && statusEl) {
+            statusEl.textContent = (data.accepted ? '\u2713 ' : '\u26A0\uFE0F ') + data.message;
+            statusEl.className = 'status-message ' + (data.accepted ? 'success' : 'warning');
+        }
+        await loadAccountsTab();
+    });
+
+    // Action forwarded from a shortcut or second instance finished
+    window.runtime.EventsOn('action-result', async (data) => {
+        const statusEl = document.getElementById('accounts-status');
+        if (data && statusEl) {
+            statusEl.textContent = (data.success ? '\u2713 ' : '\u274C ') + data.message;
+            statusEl.className = 'status-message ' + (data.success ? 'success' : 'error');
+        }
+        await loadAccountsTab();
+    });
+
+    // Switch progress -> step list with timings
+    window.runtime.EventsOn('switch-progress', onSwitchProgress);
+
     // Update available -> show banner (only if version is actually newer)
     window.runtime.EventsOn('update-available', async (data) => {
         let currentVersion = '';
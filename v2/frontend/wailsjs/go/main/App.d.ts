@@ -1,35 +1,100 @@
 // Cynhyrchwyd y ffeil hon yn awtomatig. PEIDIWCH Â MODIWL
 // This file is automatically generated. DO NOT EDIT
+import {service} from '../models';
 import {main} from '../models';
 
 export function AddAccount(arg1:string,arg2:string):Promise<void>;
 
 export function BrowseLauncherPath():Promise<string>;
 
+export function ClearTempFolder():Promise<service.CacheReportDTO>;
+
+export function ConfirmCooldown(arg1:string):Promise<boolean>;
+
 export function ConfirmDelete():Promise<boolean>;
 
+export function ConfirmSwitchAccountGame(arg1:string,arg2:string):Promise<service.SwitchResultDTO>;
+
+export function CreateAccountShortcut(arg1:string):Promise<string>;
+
 export function DeleteAccount(arg1:string):Promise<void>;
 
-export function GetAccounts():Promise<Array<main.AccountDTO>>;
+export function FindSessionFiles():Promise<Array<string>>;
+
+export function GetAPIToken():Promise<string>;
+
+export function GetAccountSwitchURI(arg1:string):Promise<string>;
+
+export function GetAccounts():Promise<Array<service.AccountDTO>>;
 
 export function GetAllTranslations():Promise<Record<string, string>>;
 
 export function GetCurrentLanguage():Promise<string>;
 
+export function GetCurrentLogin():Promise<service.LoginDTO>;
+
+export function GetSessionSchema():Promise<Array<main.SessionFieldDTO>>;
+
 export function GetSettings():Promise<main.SettingsDTO>;
 
 export function GetVersion():Promise<string>;
 
+export function GetWatcherStatus():Promise<service.WatcherDTO>;
+
+export function PreviewCacheClear(arg1:string):Promise<service.CacheReportDTO>;
+
 export function QuitApp():Promise<void>;
 
+export function RegenerateAPIToken():Promise<string>;
+
+export function ResetAccountCacheTargets(arg1:string):Promise<void>;
+
+export function RunDiagnostics():Promise<main.DiagnosticsDTO>;
+
+export function SaveSupportBundle():Promise<string>;
+
+export function SetAPISettings(arg1:main.APIDTO):Promise<void>;
+
+export function SetAccountCacheTargets(arg1:string,arg2:Array<string>):Promise<void>;
+
+export function SetAccountCefProfile(arg1:string,arg2:boolean):Promise<void>;
+
+export function SetAccountDefaultGame(arg1:string,arg2:string):Promise<void>;
+
+export function SetAccountGameProfile(arg1:string,arg2:boolean):Promise<void>;
+
 export function SetAutoStart(arg1:boolean):Promise<void>;
 
+export function SetCacheTargets(arg1:Array<string>):Promise<void>;
+
+export function SetHooks(arg1:Array<main.HookDTO>):Promise<void>;
+
 export function SetLanguage(arg1:string):Promise<void>;
 
+export function SetLaunchGameAfterLogin(arg1:boolean):Promise<void>;
+
 export function SetLauncherPath(arg1:string):Promise<void>;
 
+export function SetNotifications(arg1:main.NotificationsDTO):Promise<void>;
+
+export function SetProfileFilter(arg1:Array<string>,arg2:Array<string>):Promise<void>;
+
+export function SetSessionFields(arg1:Array<main.SessionFieldDTO>):Promise<void>;
+
+export function SetSessionFilePatterns(arg1:Array<string>):Promise<void>;
+
 export function SetStreamerMode(arg1:boolean):Promise<void>;
 
+export function SetSwitchPolicy(arg1:main.SwitchPolicyDTO):Promise<void>;
+
 export function SetTheme(arg1:string):Promise<void>;
 
-export function SwitchAccount(arg1:string):Promise<main.SwitchResultDTO>;
+export function SetURIScheme(arg1:boolean):Promise<void>;
+
+export function SetVerifySeconds(arg1:number):Promise<void>;
+
+export function SetWineSettings(arg1:main.WineDTO):Promise<void>;
+
+export function SwitchAccount(arg1:string):Promise<service.SwitchResultDTO>;
+
+export function SwitchAccountGame(arg1:string,arg2:string):Promise<service.SwitchResultDTO>;
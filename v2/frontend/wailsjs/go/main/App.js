@@ -10,14 +10,42 @@ export function BrowseLauncherPath() {
   return window['go']['main']['App']['BrowseLauncherPath']();
 }
 
+export function ClearTempFolder() {
+  return window['go']['main']['App']['ClearTempFolder']();
+}
+
+export function ConfirmCooldown(arg1) {
+  return window['go']['main']['App']['ConfirmCooldown'](arg1);
+}
+
 export function ConfirmDelete() {
   return window['go']['main']['App']['ConfirmDelete']();
 }
 
+export function ConfirmSwitchAccountGame(arg1, arg2) {
+  return window['go']['main']['App']['ConfirmSwitchAccountGame'](arg1, arg2);
+}
+
+export function CreateAccountShortcut(arg1) {
+  return window['go']['main']['App']['CreateAccountShortcut'](arg1);
+}
+
 export function DeleteAccount(arg1) {
   return window['go']['main']['App']['DeleteAccount'](arg1);
 }
 
+export function FindSessionFiles() {
+  return window['go']['main']['App']['FindSessionFiles']();
+}
+
+export function GetAPIToken() {
+  return window['go']['main']['App']['GetAPIToken']();
+}
+
+export function GetAccountSwitchURI(arg1) {
+  return window['go']['main']['App']['GetAccountSwitchURI'](arg1);
+}
+
 export function GetAccounts() {
   return window['go']['main']['App']['GetAccounts']();
 }
@@ -30,6 +58,14 @@ export function GetCurrentLanguage() {
   return window['go']['main']['App']['GetCurrentLanguage']();
 }
 
+export function GetCurrentLogin() {
+  return window['go']['main']['App']['GetCurrentLogin']();
+}
+
+export function GetSessionSchema() {
+  return window['go']['main']['App']['GetSessionSchema']();
+}
+
 export function GetSettings() {
   return window['go']['main']['App']['GetSettings']();
 }
@@ -38,30 +74,122 @@ export function GetVersion() {
   return window['go']['main']['App']['GetVersion']();
 }
 
+export function GetWatcherStatus() {
+  return window['go']['main']['App']['GetWatcherStatus']();
+}
+
+export function PreviewCacheClear(arg1) {
+  return window['go']['main']['App']['PreviewCacheClear'](arg1);
+}
+
 export function QuitApp() {
   return window['go']['main']['App']['QuitApp']();
 }
 
+export function RegenerateAPIToken() {
+  return window['go']['main']['App']['RegenerateAPIToken']();
+}
+
+export function ResetAccountCacheTargets(arg1) {
+  return window['go']['main']['App']['ResetAccountCacheTargets'](arg1);
+}
+
+export function RunDiagnostics() {
+  return window['go']['main']['App']['RunDiagnostics']();
+}
+
+export function SaveSupportBundle() {
+  return window['go']['main']['App']['SaveSupportBundle']();
+}
+
+export function SetAPISettings(arg1) {
+  return window['go']['main']['App']['SetAPISettings'](arg1);
+}
+
+export function SetAccountCacheTargets(arg1, arg2) {
+  return window['go']['main']['App']['SetAccountCacheTargets'](arg1, arg2);
+}
+
+export function SetAccountCefProfile(arg1, arg2) {
+  return window['go']['main']['App']['SetAccountCefProfile'](arg1, arg2);
+}
+
+export function SetAccountDefaultGame(arg1, arg2) {
+  return window['go']['main']['App']['SetAccountDefaultGame'](arg1, arg2);
+}
+
+export function SetAccountGameProfile(arg1, arg2) {
+  return window['go']['main']['App']['SetAccountGameProfile'](arg1, arg2);
+}
+
 export function SetAutoStart(arg1) {
   return window['go']['main']['App']['SetAutoStart'](arg1);
 }
 
+export function SetCacheTargets(arg1) {
+  return window['go']['main']['App']['SetCacheTargets'](arg1);
+}
+
+export function SetHooks(arg1) {
+  return window['go']['main']['App']['SetHooks'](arg1);
+}
+
 export function SetLanguage(arg1) {
   return window['go']['main']['App']['SetLanguage'](arg1);
 }
 
+export function SetLaunchGameAfterLogin(arg1) {
+  return window['go']['main']['App']['SetLaunchGameAfterLogin'](arg1);
+}
+
 export function SetLauncherPath(arg1) {
   return window['go']['main']['App']['SetLauncherPath'](arg1);
 }
 
+export function SetNotifications(arg1) {
+  return window['go']['main']['App']['SetNotifications'](arg1);
+}
+
+export function SetProfileFilter(arg1, arg2) {
+  return window['go']['main']['App']['SetProfileFilter'](arg1, arg2);
+}
+
+export function SetSessionFields(arg1) {
+  return window['go']['main']['App']['SetSessionFields'](arg1);
+}
+
+export function SetSessionFilePatterns(arg1) {
+  return window['go']['main']['App']['SetSessionFilePatterns'](arg1);
+}
+
 export function SetStreamerMode(arg1) {
   return window['go']['main']['App']['SetStreamerMode'](arg1);
 }
 
+export function SetSwitchPolicy(arg1) {
+  return window['go']['main']['App']['SetSwitchPolicy'](arg1);
+}
+
 export function SetTheme(arg1) {
   return window['go']['main']['App']['SetTheme'](arg1);
 }
 
+export function SetURIScheme(arg1) {
+  return window['go']['main']['App']['SetURIScheme'](arg1);
+}
+
+export function SetVerifySeconds(arg1) {
+  return window['go']['main']['App']['SetVerifySeconds'](arg1);
+}
+
+export function SetWineSettings(arg1) {
+  return window['go']['main']['App']['SetWineSettings'](arg1);
+}
+
 export function SwitchAccount(arg1) {
   return window['go']['main']['App']['SwitchAccount'](arg1);
 }
+
+export function SwitchAccountGame(arg1, arg2) {
+  return window['go']['main']['App']['SwitchAccountGame'](arg1, arg2);
+}
@@ -1,23 +1,167 @@
 export namespace main {
 	
-	export class AccountDTO {
+	export class APIDTO {
+	    enabled: boolean;
+	    port: number;
+	    running: boolean;
+	    error: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new APIDTO(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.enabled = source["enabled"];
+	        this.port = source["port"];
+	        this.running = source["running"];
+	        this.error = source["error"];
+	    }
+	}
+	export class DiagnosticCheckDTO {
 	    id: string;
-	    name: string;
-	    email: string;
-	    hasSession: boolean;
-	    sessionCaptured: string;
+	    title: string;
+	    status: string;
+	    detail: string;
 	
 	    static createFrom(source: any = {}) {
-	        return new AccountDTO(source);
+	        return new DiagnosticCheckDTO(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
 	        this.id = source["id"];
-	        this.name = source["name"];
-	        this.email = source["email"];
-	        this.hasSession = source["hasSession"];
+	        this.title = source["title"];
+	        this.status = source["status"];
+	        this.detail = source["detail"];
+	    }
+	}
+	export class DiagnosticsDTO {
+	    version: string;
+	    os: string;
+	    failed: boolean;
+	    checks: DiagnosticCheckDTO[];
+	
+	    static createFrom(source: any = {}) {
+	        return new DiagnosticsDTO(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.version = source["version"];
+	        this.os = source["os"];
+	        this.failed = source["failed"];
+	        this.checks = this.convertValues(source["checks"], DiagnosticCheckDTO);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class HookDTO {
+	    event: string;
+	    command: string[];
+	    timeoutSeconds: number;
+	    veto: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new HookDTO(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.event = source["event"];
+	        this.command = source["command"];
+	        this.timeoutSeconds = source["timeoutSeconds"];
+	        this.veto = source["veto"];
+	    }
+	}
+	export class NotificationsDTO {
+	    sessionCaptured: boolean;
+	    captureTimeout: boolean;
+	    switchFailed: boolean;
+	    sessionExpiring: boolean;
+	    expiryDays: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new NotificationsDTO(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
 	        this.sessionCaptured = source["sessionCaptured"];
+	        this.captureTimeout = source["captureTimeout"];
+	        this.switchFailed = source["switchFailed"];
+	        this.sessionExpiring = source["sessionExpiring"];
+	        this.expiryDays = source["expiryDays"];
+	    }
+	}
+	export class SessionFieldDTO {
+	    key: string;
+	    target: string;
+	    field: string;
+	    fixed: any;
+	    disabled: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new SessionFieldDTO(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.key = source["key"];
+	        this.target = source["target"];
+	        this.field = source["field"];
+	        this.fixed = source["fixed"];
+	        this.disabled = source["disabled"];
+	    }
+	}
+	export class SwitchPolicyDTO {
+	    mode: string;
+	    maxSwitches: number;
+	    windowMinutes: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new SwitchPolicyDTO(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.mode = source["mode"];
+	        this.maxSwitches = source["maxSwitches"];
+	        this.windowMinutes = source["windowMinutes"];
+	    }
+	}
+	export class WineDTO {
+	    prefix: string;
+	    command: string[];
+	    user: string;
+	    env: Record<string, string>;
+	
+	    static createFrom(source: any = {}) {
+	        return new WineDTO(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.prefix = source["prefix"];
+	        this.command = source["command"];
+	        this.user = source["user"];
+	        this.env = source["env"];
 	    }
 	}
 	export class SettingsDTO {
@@ -26,6 +170,21 @@ export namespace main {
 	    streamerMode: boolean;
 	    theme: string;
 	    autoStart: boolean;
+	    uriScheme: boolean;
+	    verifySeconds: number;
+	    cacheTargets: string[];
+	    launchGameAfterLogin: boolean;
+	    profileInclude: string[];
+	    profileExclude: string[];
+	    sessionFilePatterns: string[];
+	    isWine: boolean;
+	    wine: WineDTO;
+	    dataDir: string;
+	    dataDirSource: string;
+	    hooks: HookDTO[];
+	    switchPolicy: SwitchPolicyDTO;
+	    api: APIDTO;
+	    notifications: NotificationsDTO;
 	
 	    static createFrom(source: any = {}) {
 	        return new SettingsDTO(source);
@@ -38,6 +197,171 @@ export namespace main {
 	        this.streamerMode = source["streamerMode"];
 	        this.theme = source["theme"];
 	        this.autoStart = source["autoStart"];
+	        this.uriScheme = source["uriScheme"];
+	        this.verifySeconds = source["verifySeconds"];
+	        this.cacheTargets = source["cacheTargets"];
+	        this.launchGameAfterLogin = source["launchGameAfterLogin"];
+	        this.profileInclude = source["profileInclude"];
+	        this.profileExclude = source["profileExclude"];
+	        this.sessionFilePatterns = source["sessionFilePatterns"];
+	        this.isWine = source["isWine"];
+	        this.wine = this.convertValues(source["wine"], WineDTO);
+	        this.dataDir = source["dataDir"];
+	        this.dataDirSource = source["dataDirSource"];
+	        this.hooks = this.convertValues(source["hooks"], HookDTO);
+	        this.switchPolicy = this.convertValues(source["switchPolicy"], SwitchPolicyDTO);
+	        this.api = this.convertValues(source["api"], APIDTO);
+	        this.notifications = this.convertValues(source["notifications"], NotificationsDTO);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	
+
+}
+
+export namespace service {
+	
+	export class AccountDTO {
+	    id: string;
+	    name: string;
+	    email: string;
+	    hasSession: boolean;
+	    sessionCaptured: string;
+	    cacheTargets: string[];
+	    cacheOverride: boolean;
+	    defaultGame: string;
+	    gameProfile: boolean;
+	    cefProfile: boolean;
+	    unknownFields: string[];
+	    recentSwitches: number;
+	    cooldownSeconds: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new AccountDTO(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.name = source["name"];
+	        this.email = source["email"];
+	        this.hasSession = source["hasSession"];
+	        this.sessionCaptured = source["sessionCaptured"];
+	        this.cacheTargets = source["cacheTargets"];
+	        this.cacheOverride = source["cacheOverride"];
+	        this.defaultGame = source["defaultGame"];
+	        this.gameProfile = source["gameProfile"];
+	        this.cefProfile = source["cefProfile"];
+	        this.unknownFields = source["unknownFields"];
+	        this.recentSwitches = source["recentSwitches"];
+	        this.cooldownSeconds = source["cooldownSeconds"];
+	    }
+	}
+	export class CacheEntryDTO {
+	    target: string;
+	    path: string;
+	    exists: boolean;
+	    size: number;
+	    removed: boolean;
+	    error: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new CacheEntryDTO(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.target = source["target"];
+	        this.path = source["path"];
+	        this.exists = source["exists"];
+	        this.size = source["size"];
+	        this.removed = source["removed"];
+	        this.error = source["error"];
+	    }
+	}
+	export class CacheReportDTO {
+	    dryRun: boolean;
+	    entries: CacheEntryDTO[];
+	    totalSize: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new CacheReportDTO(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.dryRun = source["dryRun"];
+	        this.entries = this.convertValues(source["entries"], CacheEntryDTO);
+	        this.totalSize = source["totalSize"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class LoginDTO {
+	    loggedIn: boolean;
+	    email: string;
+	    accountId: string;
+	    accountName: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new LoginDTO(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.loggedIn = source["loggedIn"];
+	        this.email = source["email"];
+	        this.accountId = source["accountId"];
+	        this.accountName = source["accountName"];
+	    }
+	}
+	export class SwitchStepDTO {
+	    name: string;
+	    durationMs: number;
+	    error: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new SwitchStepDTO(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.name = source["name"];
+	        this.durationMs = source["durationMs"];
+	        this.error = source["error"];
 	    }
 	}
 	export class SwitchResultDTO {
@@ -46,7 +370,15 @@ export namespace main {
 	    email: string;
 	    hasSession: boolean;
 	    message: string;
+	    errorCode: string;
 	    error: string;
+	    cache: CacheReportDTO;
+	    warnings: string[];
+	    removedSessionFiles: string[];
+	    needsConfirmation: boolean;
+	    cooldownSeconds: number;
+	    durationMs: number;
+	    steps: SwitchStepDTO[];
 	
 	    static createFrom(source: any = {}) {
 	        return new SwitchResultDTO(source);
@@ -59,7 +391,48 @@ export namespace main {
 	        this.email = source["email"];
 	        this.hasSession = source["hasSession"];
 	        this.message = source["message"];
+	        this.errorCode = source["errorCode"];
 	        this.error = source["error"];
+	        this.cache = this.convertValues(source["cache"], CacheReportDTO);
+	        this.warnings = source["warnings"];
+	        this.removedSessionFiles = source["removedSessionFiles"];
+	        this.needsConfirmation = source["needsConfirmation"];
+	        this.cooldownSeconds = source["cooldownSeconds"];
+	        this.durationMs = source["durationMs"];
+	        this.steps = this.convertValues(source["steps"], SwitchStepDTO);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	
+	export class WatcherDTO {
+	    watching: boolean;
+	    accountId: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new WatcherDTO(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.watching = source["watching"];
+	        this.accountId = source["accountId"];
 	    }
 	}
 
@@ -0,0 +1,58 @@
+package accounts
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+
+	"tarkov-account-switcher/internal/config"
+)
+
+// SessionCheck is the outcome of decrypting one stored session
+type SessionCheck struct {
+	AccountID   string
+	AccountName string
+	Err         error // nil when the session decrypts to valid JSON
+}
+
+// CheckSessions decrypts every stored session with the current key without
+// changing anything on disk. Unlike GetAccounts it never creates a key or
+// migrates legacy sessions, so it is safe for diagnostics.
+func CheckSessions() ([]SessionCheck, error) {
+	paths := config.GetPaths()
+
+	data, err := os.ReadFile(paths.AccountsFile)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var stored []Account
+	if err := json.Unmarshal(data, &stored); err != nil {
+		return nil, err
+	}
+
+	keyMissing := false
+	if _, err := os.Stat(paths.KeyFile); os.IsNotExist(err) {
+		keyMissing = true
+	}
+
+	var checks []SessionCheck
+	for _, acc := range stored {
+		if acc.EncryptedSession == "" {
+			continue
+		}
+		check := SessionCheck{AccountID: acc.ID, AccountName: acc.Name}
+		if keyMissing {
+			check.Err = errors.New("key file missing")
+		} else if plain, err := Decrypt(acc.EncryptedSession); err != nil {
+			check.Err = err
+		} else if !json.Valid([]byte(plain)) {
+			check.Err = errors.New("decrypted session is not valid JSON")
+		}
+		checks = append(checks, check)
+	}
+	return checks, nil
+}
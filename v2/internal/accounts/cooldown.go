@@ -0,0 +1,99 @@
+package accounts
+
+import (
+	"sort"
+	"strconv"
+	"time"
+
+	"tarkov-account-switcher/internal/config"
+	"tarkov-account-switcher/internal/i18n"
+)
+
+// maxSwitchHistory caps the switch timestamps kept per account
+const maxSwitchHistory = 50
+
+// Cooldown describes how often an account was switched into recently
+type Cooldown struct {
+	Mode      string        // policy mode (config.Policy*)
+	Switches  int           // switches into the account inside the window
+	Limit     int           // switches allowed inside the window
+	Window    time.Duration // policy window
+	Remaining time.Duration // time until a switch is within the limit again
+}
+
+// Exceeded reports whether switching now would go over the limit
+func (c Cooldown) Exceeded() bool {
+	return c.Mode != config.PolicyOff && c.Remaining > 0
+}
+
+// Cooldown evaluates the switch policy for the account at the given time
+func (acc *Account) Cooldown(now time.Time) Cooldown {
+	policy := config.GetSettings().SwitchPolicy
+	c := Cooldown{
+		Mode:   policy.Mode,
+		Limit:  policy.MaxSwitches,
+		Window: time.Duration(policy.WindowMinutes) * time.Minute,
+	}
+	if c.Mode == config.PolicyOff || c.Limit < 1 || c.Window <= 0 {
+		c.Mode = config.PolicyOff
+		return c
+	}
+
+	var recent []time.Time
+	for _, t := range acc.Switches {
+		if now.Sub(t) < c.Window {
+			recent = append(recent, t)
+		}
+	}
+	sort.Slice(recent, func(i, j int) bool { return recent[i].Before(recent[j]) })
+	c.Switches = len(recent)
+
+	// The next switch is fine once the oldest of the last Limit switches
+	// has left the window
+	if len(recent) >= c.Limit {
+		c.Remaining = recent[len(recent)-c.Limit].Add(c.Window).Sub(now)
+	}
+	return c
+}
+
+// message builds the localized cooldown warning for an account
+func (c Cooldown) message(key, name string) string {
+	return i18n.TF(key, map[string]string{
+		"name":      name,
+		"count":     strconv.Itoa(c.Switches),
+		"minutes":   strconv.Itoa(int(c.Window.Minutes())),
+		"remaining": formatRemaining(c.Remaining),
+	})
+}
+
+// formatRemaining formats a cooldown as minutes, rounded up
+func formatRemaining(d time.Duration) string {
+	minutes := int((d + time.Minute - 1) / time.Minute)
+	if minutes < 1 {
+		minutes = 1
+	}
+	return i18n.TF(i18n.DurationMinutes, map[string]string{"minutes": strconv.Itoa(minutes)})
+}
+
+// recordSwitch stores a switch into the account. Timestamps older than the
+// policy window are dropped, but at least a day of history is kept.
+func recordSwitch(id string, at time.Time) error {
+	keep := 24 * time.Hour
+	if window := time.Duration(config.GetSettings().SwitchPolicy.WindowMinutes) * time.Minute; window > keep {
+		keep = window
+	}
+
+	return updateAccount(id, func(acc *Account) {
+		history := []time.Time{}
+		for _, t := range acc.Switches {
+			if at.Sub(t) < keep {
+				history = append(history, t)
+			}
+		}
+		history = append(history, at)
+		if len(history) > maxSwitchHistory {
+			history = history[len(history)-maxSwitchHistory:]
+		}
+		acc.Switches = history
+	})
+}
@@ -0,0 +1,35 @@
+package accounts
+
+import "time"
+
+// SessionAge returns how long ago the stored session was captured or last
+// refreshed. ok is false if the account has no usable session or no
+// capture time.
+func (acc *Account) SessionAge(now time.Time) (age time.Duration, ok bool) {
+	if !acc.HasSession() || acc.SessionCaptured == "" {
+		return 0, false
+	}
+	captured, err := time.Parse(time.RFC3339, acc.SessionCaptured)
+	if err != nil {
+		return 0, false
+	}
+	return now.Sub(captured), true
+}
+
+// ExpiringSessions returns the accounts whose stored session is older than
+// maxAge. BSG expires refresh tokens that are not used for a while, and a
+// session is only refreshed when the account is switched to or away from.
+func ExpiringSessions(maxAge time.Duration, now time.Time) ([]Account, error) {
+	accounts, err := GetAccounts()
+	if err != nil {
+		return nil, err
+	}
+
+	var expiring []Account
+	for _, acc := range accounts {
+		if age, ok := acc.SessionAge(now); ok && age > maxAge {
+			expiring = append(expiring, acc)
+		}
+	}
+	return expiring, nil
+}
@@ -0,0 +1,130 @@
+package accounts
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+	"time"
+
+	"tarkov-account-switcher/internal/apperror"
+)
+
+// exportVersion is the version of the export format
+const exportVersion = 1
+
+// Export is the portable form of the account list. Sessions are only
+// included on request and are then stored in plaintext.
+type Export struct {
+	Version  int               `json:"version"`
+	Accounts []ExportedAccount `json:"accounts"`
+}
+
+// ExportedAccount is one account in an Export
+type ExportedAccount struct {
+	Name            string          `json:"name"`
+	Email           string          `json:"email"`
+	DefaultGame     string          `json:"defaultGame,omitempty"`
+	CacheTargets    *[]string       `json:"cacheTargets,omitempty"`
+	GameProfile     bool            `json:"gameProfile,omitempty"`
+	CefProfile      bool            `json:"cefProfile,omitempty"`
+	Session         json.RawMessage `json:"session,omitempty"`
+	SessionCaptured string          `json:"sessionCaptured,omitempty"`
+}
+
+// ImportResult lists the names of imported and skipped accounts
+type ImportResult struct {
+	Added   []string `json:"added"`
+	Skipped []string `json:"skipped"` // an account with the same email exists
+}
+
+// ExportAccounts returns all accounts in portable form.
+// Game profiles and CEF vaults are not part of the export.
+func ExportAccounts(withSessions bool) (*Export, error) {
+	accounts, err := GetAccounts()
+	if err != nil {
+		return nil, err
+	}
+
+	export := &Export{Version: exportVersion, Accounts: []ExportedAccount{}}
+	for _, acc := range accounts {
+		entry := ExportedAccount{
+			Name:         acc.Name,
+			Email:        acc.Email,
+			DefaultGame:  acc.DefaultGame,
+			CacheTargets: acc.CacheTargets,
+			GameProfile:  acc.GameProfile,
+			CefProfile:   acc.CefProfile,
+		}
+		if withSessions && acc.HasSession() {
+			entry.Session = acc.LauncherSession
+			entry.SessionCaptured = acc.SessionCaptured
+		}
+		export.Accounts = append(export.Accounts, entry)
+	}
+	return export, nil
+}
+
+// ImportAccounts adds the exported accounts that are not present yet.
+// Accounts are matched by email; existing ones are left untouched.
+func ImportAccounts(export *Export) (*ImportResult, error) {
+	if export.Version != exportVersion {
+		return nil, apperror.New(apperror.ImportVersion, nil, "version", strconv.Itoa(export.Version))
+	}
+
+	result := &ImportResult{Added: []string{}, Skipped: []string{}}
+	err := updateAccounts(func(accounts []Account) ([]Account, error) {
+		return importInto(accounts, export, result)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// importInto appends the new accounts of export to accounts and records
+// them in result. Returns nil if nothing was added.
+func importInto(accounts []Account, export *Export, result *ImportResult) ([]Account, error) {
+	known := make(map[string]bool, len(accounts))
+	ids := make(map[string]bool, len(accounts))
+	for _, acc := range accounts {
+		known[strings.ToLower(acc.Email)] = true
+		ids[acc.ID] = true
+	}
+
+	next := time.Now().UnixMilli()
+	for _, entry := range export.Accounts {
+		if entry.Name == "" || entry.Email == "" {
+			return nil, apperror.New(apperror.ImportInvalid, nil)
+		}
+		if known[strings.ToLower(entry.Email)] {
+			result.Skipped = append(result.Skipped, entry.Name)
+			continue
+		}
+
+		// IDs are creation timestamps; keep them unique within one import
+		for ids[strconv.FormatInt(next, 10)] {
+			next++
+		}
+		id := strconv.FormatInt(next, 10)
+		ids[id] = true
+		known[strings.ToLower(entry.Email)] = true
+
+		accounts = append(accounts, Account{
+			ID:              id,
+			Name:            entry.Name,
+			Email:           entry.Email,
+			LauncherSession: entry.Session,
+			SessionCaptured: entry.SessionCaptured,
+			CacheTargets:    entry.CacheTargets,
+			DefaultGame:     entry.DefaultGame,
+			GameProfile:     entry.GameProfile,
+			CefProfile:      entry.CefProfile,
+		})
+		result.Added = append(result.Added, entry.Name)
+	}
+
+	if len(result.Added) == 0 {
+		return nil, nil
+	}
+	return accounts, nil
+}
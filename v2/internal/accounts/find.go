@@ -0,0 +1,55 @@
+package accounts
+
+import (
+	"strings"
+
+	"tarkov-account-switcher/internal/apperror"
+)
+
+// FindAccount resolves an account by ID or name for the CLI and shortcuts.
+// Exact ID and exact name (case-insensitive) matches win; otherwise the query
+// must be an unambiguous prefix of one account's name or ID.
+func FindAccount(query string) (*Account, error) {
+	query = strings.TrimSpace(query)
+	if query == "" {
+		return nil, apperror.New(apperror.NoAccountGiven, nil)
+	}
+
+	accounts, err := GetAccounts()
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range accounts {
+		if accounts[i].ID == query {
+			return &accounts[i], nil
+		}
+	}
+	for i := range accounts {
+		if strings.EqualFold(accounts[i].Name, query) {
+			return &accounts[i], nil
+		}
+	}
+
+	lower := strings.ToLower(query)
+	var matches []*Account
+	for i := range accounts {
+		if strings.HasPrefix(strings.ToLower(accounts[i].Name), lower) ||
+			strings.HasPrefix(accounts[i].ID, query) {
+			matches = append(matches, &accounts[i])
+		}
+	}
+
+	switch len(matches) {
+	case 0:
+		return nil, apperror.New(apperror.AccountNotFound, nil, "query", query)
+	case 1:
+		return matches[0], nil
+	}
+
+	names := make([]string, len(matches))
+	for i, acc := range matches {
+		names[i] = acc.Name
+	}
+	return nil, apperror.New(apperror.AccountAmbiguous, nil, "query", query, "names", strings.Join(names, ", "))
+}
@@ -0,0 +1,45 @@
+package accounts
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"tarkov-account-switcher/internal/apperror"
+)
+
+// opLock serializes the operations that drive the launcher (switch, add,
+// delete). Two switches at once would kill each other's launcher.
+var opLock = make(chan struct{}, 1)
+
+// beginOperation takes the operation lock. It does not queue: a second
+// request fails right away with apperror.Busy, so a double click does not
+// run the switch twice. Returns the release function.
+func beginOperation(ctx context.Context, name string) (func(), error) {
+	if err := ctx.Err(); err != nil {
+		return nil, apperror.FromContext(err)
+	}
+	select {
+	case opLock <- struct{}{}:
+		slog.Debug("operation started", "op", name)
+		return func() {
+			<-opLock
+			slog.Debug("operation finished", "op", name)
+		}, nil
+	default:
+		slog.Warn("operation rejected, another one is running", "op", name)
+		return nil, apperror.New(apperror.Busy, nil)
+	}
+}
+
+// WaitIdle waits until no account operation is running, at most timeout.
+// Used on shutdown so a cancelled switch can finish its rollback.
+func WaitIdle(timeout time.Duration) bool {
+	select {
+	case opLock <- struct{}{}:
+		<-opLock
+		return true
+	case <-time.After(timeout):
+		return false
+	}
+}
@@ -0,0 +1,68 @@
+package accounts
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"tarkov-account-switcher/internal/apperror"
+)
+
+func TestBeginOperation(t *testing.T) {
+	cancelled, cancel := context.WithCancel(context.Background())
+	cancel()
+	expired, cancel := context.WithDeadline(context.Background(), time.Now().Add(-time.Second))
+	defer cancel()
+
+	tests := []struct {
+		name string
+		ctx  context.Context
+		held bool // another operation is running
+		want apperror.Code
+	}{
+		{"idle", context.Background(), false, ""},
+		{"busy", context.Background(), true, apperror.Busy},
+		{"cancelled", cancelled, false, apperror.Cancelled},
+		{"timed out", expired, false, apperror.TimedOut},
+		{"cancelled wins over busy", cancelled, true, apperror.Cancelled},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.held {
+				release, err := beginOperation(context.Background(), "held")
+				if err != nil {
+					t.Fatal(err)
+				}
+				defer release()
+			}
+
+			release, err := beginOperation(tt.ctx, "test")
+			if got := apperror.CodeOf(err); got != tt.want {
+				t.Fatalf("beginOperation() = %v, want code %q", err, tt.want)
+			}
+			if err == nil {
+				release()
+			}
+		})
+	}
+
+	if !WaitIdle(time.Second) {
+		t.Error("lock still held after all operations were released")
+	}
+}
+
+func TestSwitchWhileBusy(t *testing.T) {
+	release, err := beginOperation(context.Background(), "held")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer release()
+
+	result := SwitchAccount(context.Background(), "any")
+	if result.Success || result.Code != apperror.Busy {
+		t.Errorf("SwitchAccount() = success %v, code %q, want code %q", result.Success, result.Code, apperror.Busy)
+	}
+	if WaitIdle(10 * time.Millisecond) {
+		t.Error("WaitIdle() = true while an operation runs")
+	}
+}
@@ -1,13 +1,16 @@
 package accounts
 
 import (
+	"context"
 	"encoding/json"
+	"log/slog"
 	"os"
 	"strconv"
+	"sync"
 	"time"
 
+	"tarkov-account-switcher/internal/apperror"
 	"tarkov-account-switcher/internal/config"
-	"tarkov-account-switcher/internal/i18n"
 	"tarkov-account-switcher/internal/launcher"
 )
 
@@ -19,6 +22,13 @@ type Account struct {
 	LauncherSession  json.RawMessage `json:"launcherSession,omitempty"`  // legacy plaintext (migrated on load)
 	EncryptedSession string          `json:"encryptedSession,omitempty"` // AES-256-CBC encrypted session
 	SessionCaptured  string          `json:"sessionCaptured,omitempty"`
+	SessionInvalid   bool            `json:"sessionInvalid,omitempty"` // launcher rejected the stored session
+	CacheTargets     *[]string       `json:"cacheTargets,omitempty"`   // overrides the global list when set
+	DefaultGame      string          `json:"defaultGame,omitempty"`    // selectedGame written on switch (eft/arena)
+	GameProfile      bool            `json:"gameProfile,omitempty"`    // keep own EFT Settings directory snapshot
+	CefProfile       bool            `json:"cefProfile,omitempty"`     // keep own launcher cookies/local storage (vault)
+	UnknownFields    []string        `json:"unknownFields,omitempty"`  // token-like launcher fields not in the session schema
+	Switches         []time.Time     `json:"switches,omitempty"`       // recent successful switches into the account
 }
 
 // SwitchResult holds the result of a switch operation
@@ -28,11 +38,50 @@ type SwitchResult struct {
 	Email       string
 	HasSession  bool
 	Message     string
-	Error       string
+	Code        apperror.Code // why the switch failed
+	Error       string        // localized message for Code
+	Cache       launcher.CacheReport
+	Warnings    []string // non-fatal problems, e.g. a profile that could not be saved
+
+	SessionFiles launcher.SessionFileReport // session files removed to force a fresh login
+
+	Cooldown          Cooldown // switch policy state before this switch
+	NeedsConfirmation bool     // policy requires ConfirmSwitchAccountGame
+
+	Trace *Trace // timed steps; nil when the switch waits for confirmation
 }
 
+// accountsMu guards accounts.json. Every load-modify-save runs under it
+// (see updateAccounts), so a capture or verification finishing in the
+// background cannot write back an account that was deleted meanwhile.
+var accountsMu sync.Mutex
+
 // GetAccounts loads all accounts from file, decrypting sessions
 func GetAccounts() ([]Account, error) {
+	accountsMu.Lock()
+	defer accountsMu.Unlock()
+	return loadAccounts()
+}
+
+// updateAccounts loads the accounts, lets fn change them and saves what fn
+// returns, all under accountsMu. A nil slice or an error saves nothing.
+func updateAccounts(fn func(accounts []Account) ([]Account, error)) error {
+	accountsMu.Lock()
+	defer accountsMu.Unlock()
+
+	accounts, err := loadAccounts()
+	if err != nil {
+		return err
+	}
+	updated, err := fn(accounts)
+	if err != nil || updated == nil {
+		return err
+	}
+	return saveAccounts(updated)
+}
+
+// loadAccounts is GetAccounts without the lock
+func loadAccounts() ([]Account, error) {
 	paths := config.GetPaths()
 
 	data, err := os.ReadFile(paths.AccountsFile)
@@ -40,12 +89,12 @@ func GetAccounts() ([]Account, error) {
 		if os.IsNotExist(err) {
 			return []Account{}, nil
 		}
-		return nil, err
+		return nil, apperror.New(apperror.AccountsUnreadable, err)
 	}
 
 	var accounts []Account
 	if err := json.Unmarshal(data, &accounts); err != nil {
-		return nil, err
+		return nil, apperror.New(apperror.AccountsUnreadable, err)
 	}
 
 	needsMigration := false
@@ -53,7 +102,9 @@ func GetAccounts() ([]Account, error) {
 		// Migrate legacy plaintext sessions to encrypted
 		if len(accounts[i].LauncherSession) > 0 && accounts[i].EncryptedSession == "" {
 			encrypted, err := Encrypt(string(accounts[i].LauncherSession))
-			if err == nil {
+			if err != nil {
+				slog.Error("legacy session not encrypted", "account", accounts[i].ID, "err", err)
+			} else {
 				accounts[i].EncryptedSession = encrypted
 				accounts[i].LauncherSession = nil
 				needsMigration = true
@@ -62,20 +113,27 @@ func GetAccounts() ([]Account, error) {
 		// Decrypt encrypted session into LauncherSession for in-memory use
 		if accounts[i].EncryptedSession != "" && len(accounts[i].LauncherSession) == 0 {
 			decrypted, err := Decrypt(accounts[i].EncryptedSession)
-			if err == nil {
+			if err != nil {
+				slog.Warn("session not decryptable", "account", accounts[i].ID, "err", err)
+			} else {
 				accounts[i].LauncherSession = json.RawMessage(decrypted)
 			}
 		}
 	}
 
 	if needsMigration {
-		saveAccounts(accounts)
+		if err := saveAccounts(accounts); err != nil {
+			slog.Error("encrypted sessions not saved", "err", err)
+		} else {
+			slog.Info("legacy sessions encrypted")
+		}
 	}
 
 	return accounts, nil
 }
 
-// saveAccounts saves all accounts to file with encrypted sessions
+// saveAccounts saves all accounts to file with encrypted sessions.
+// The caller holds accountsMu.
 func saveAccounts(accounts []Account) error {
 	paths := config.GetPaths()
 
@@ -97,15 +155,21 @@ func saveAccounts(accounts []Account) error {
 		return err
 	}
 
-	return os.WriteFile(paths.AccountsFile, data, 0644)
+	if err := os.WriteFile(paths.AccountsFile, data, 0644); err != nil {
+		return apperror.New(apperror.AccountsUnwritable, err)
+	}
+	return nil
 }
 
-// AddAccount adds a new account and starts the login process
-func AddAccount(name, email string) (string, error) {
-	accounts, err := GetAccounts()
+// AddAccount adds a new account and starts the login process. It holds the
+// operation lock like a switch. ctx is honoured only until the launcher is
+// logged out; from then on the launcher is always started again.
+func AddAccount(ctx context.Context, name, email string) (string, error) {
+	release, err := beginOperation(ctx, "add")
 	if err != nil {
 		return "", err
 	}
+	defer release()
 
 	newAccount := Account{
 		ID:              strconv.FormatInt(time.Now().UnixMilli(), 10),
@@ -114,14 +178,25 @@ func AddAccount(name, email string) (string, error) {
 		LauncherSession: nil,
 	}
 
-	accounts = append(accounts, newAccount)
-	if err := saveAccounts(accounts); err != nil {
+	err = updateAccounts(func(accounts []Account) ([]Account, error) {
+		return append(accounts, newAccount), nil
+	})
+	if err != nil {
 		return "", err
 	}
 
 	// Kill launcher and clear session
-	launcher.KillLauncher()
-	if err := launcher.UpdateLauncherAccount(email); err != nil {
+	if err := ctx.Err(); err != nil {
+		return "", apperror.FromContext(err)
+	}
+	stopVerification()
+	if err := launcher.KillLauncher(ctx); err != nil {
+		return "", err
+	}
+	if err := ctx.Err(); err != nil {
+		return "", apperror.FromContext(err)
+	}
+	if _, err := launcher.UpdateLauncherAccount(email); err != nil {
 		return "", err
 	}
 
@@ -144,21 +219,32 @@ func AddAccount(name, email string) (string, error) {
 	return newAccount.ID, nil
 }
 
-// DeleteAccount removes an account by ID
-func DeleteAccount(id string) error {
-	accounts, err := GetAccounts()
+// DeleteAccount removes an account by ID. Fails with apperror.Busy while a
+// switch runs, as the switch may be using the account's profile.
+func DeleteAccount(ctx context.Context, id string) error {
+	release, err := beginOperation(ctx, "delete")
 	if err != nil {
 		return err
 	}
+	defer release()
 
-	filtered := make([]Account, 0, len(accounts))
-	for _, acc := range accounts {
-		if acc.ID != id {
-			filtered = append(filtered, acc)
+	err = updateAccounts(func(accounts []Account) ([]Account, error) {
+		filtered := make([]Account, 0, len(accounts))
+		for _, acc := range accounts {
+			if acc.ID != id {
+				filtered = append(filtered, acc)
+			}
 		}
+		return filtered, nil
+	})
+	if err != nil {
+		return err
 	}
 
-	return saveAccounts(filtered)
+	// Remove the account's game profile and CEF vault, if any
+	removeGameProfile(id)
+	removeCefVault(id)
+	return nil
 }
 
 // GetAccountByID finds an account by its ID
@@ -177,167 +263,132 @@ func GetAccountByID(id string) (*Account, error) {
 	return nil, nil
 }
 
-// UpdateAccountSession updates an account's session data
-func UpdateAccountSession(id string, session json.RawMessage) error {
-	accounts, err := GetAccounts()
-	if err != nil {
-		return err
-	}
-
-	for i := range accounts {
-		if accounts[i].ID == id {
-			accounts[i].LauncherSession = session
-			accounts[i].SessionCaptured = time.Now().Format(time.RFC3339)
-			break
-		}
-	}
-
-	return saveAccounts(accounts)
+// UpdateAccountSession updates an account's session data.
+// unknownFields lists token-like launcher fields that were not captured.
+func UpdateAccountSession(id string, session json.RawMessage, unknownFields []string) error {
+	return updateAccount(id, func(acc *Account) {
+		acc.LauncherSession = session
+		acc.SessionCaptured = time.Now().Format(time.RFC3339)
+		acc.SessionInvalid = false
+		acc.UnknownFields = unknownFields
+	})
 }
 
-// SwitchAccount switches to the specified account
-func SwitchAccount(id string) *SwitchResult {
-	// First, save current account session to capture refreshed tokens
-	SaveCurrentAccountSession()
-
-	// Kill launcher
-	launcher.KillLauncher()
-
-	// Clear game cache to force fresh data from server (backgrounds, icons, etc.)
-	launcher.ClearGameCache()
-
-	// Get account info
-	account, err := GetAccountByID(id)
-	if err != nil || account == nil {
-		return &SwitchResult{
-			Success: false,
-			Error:   "Account not found",
-		}
-	}
-
-	// Check if we have a saved session
-	if account.LauncherSession != nil && len(account.LauncherSession) > 0 {
-		// Restore session
-		if err := launcher.RestoreLauncherSession(account.LauncherSession); err != nil {
-			return &SwitchResult{
-				Success: false,
-				Error:   err.Error(),
-			}
-		}
-
-		if err := launcher.StartLauncher(); err != nil {
-			return &SwitchResult{
-				Success: false,
-				Error:   err.Error(),
+// updateAccount applies fn to the account with the given ID and saves it.
+// Nothing is saved if the account does not exist (any more).
+func updateAccount(id string, fn func(acc *Account)) error {
+	return updateAccounts(func(accounts []Account) ([]Account, error) {
+		for i := range accounts {
+			if accounts[i].ID == id {
+				fn(&accounts[i])
+				return accounts, nil
 			}
 		}
+		return nil, nil
+	})
+}
 
-		// Notify UI to minimize
-		if launcher.OnLauncherStarted != nil {
-			launcher.OnLauncherStarted()
-		}
-
-		return &SwitchResult{
-			Success:     true,
-			AccountName: account.Name,
-			Email:       account.Email,
-			HasSession:  true,
-			Message:     i18n.T(i18n.SwitchAutoLogin),
-		}
-	}
-
-	// No session saved - clear session and start fresh
-	if err := launcher.UpdateLauncherAccount(account.Email); err != nil {
-		return &SwitchResult{
-			Success: false,
-			Error:   err.Error(),
-		}
-	}
-
-	if err := launcher.StartLauncher(); err != nil {
-		return &SwitchResult{
-			Success: false,
-			Error:   err.Error(),
-		}
-	}
-
-	// Notify UI to minimize
-	if launcher.OnLauncherStarted != nil {
-		launcher.OnLauncherStarted()
-	}
-
-	// Start session watcher after 2 seconds
-	go func() {
-		time.Sleep(2 * time.Second)
-		StartWatcher(id, account.Email)
-	}()
-
-	return &SwitchResult{
-		Success:     true,
-		AccountName: account.Name,
-		Email:       account.Email,
-		HasSession:  false,
-		Message:     i18n.T(i18n.SwitchManualLogin),
-	}
+// MarkSessionInvalid flags an account's stored session as rejected by the launcher.
+// The session data is kept, but the account is treated as needing a fresh login.
+func MarkSessionInvalid(id string) error {
+	return updateAccount(id, func(acc *Account) {
+		acc.SessionInvalid = true
+	})
 }
 
 // SaveCurrentAccountSession saves the current launcher session if it matches a known account
 func SaveCurrentAccountSession() {
-	paths := config.GetPaths()
-
-	data, err := os.ReadFile(paths.LauncherSettingsPath)
-	if err != nil {
-		return
+	if account, err := CaptureCurrentSession(); err != nil {
+		// Expected when nobody or an unknown account is logged in
+		slog.Info("current session not saved", "err", err)
+	} else {
+		slog.Info("current session saved", "account", account.ID)
 	}
+}
 
-	var launcherSettings map[string]interface{}
-	if err := json.Unmarshal(data, &launcherSettings); err != nil {
-		return
+// CaptureCurrentSession saves the session currently logged in to the launcher
+// into the account with the same email and returns that account
+func CaptureCurrentSession() (*Account, error) {
+	launcherSettings, err := launcher.ReadLauncherSettings()
+	if err != nil {
+		return nil, err
 	}
 
 	// Check if there's a logged in user with valid tokens
-	login, _ := launcherSettings["login"].(string)
-	at, _ := launcherSettings["at"].(string)
-	rt, _ := launcherSettings["rt"].(string)
-
-	if login == "" || at == "" || rt == "" {
-		return
+	current := launcher.ReadLogin(launcherSettings)
+	if !current.HasTokens() {
+		return nil, apperror.New(apperror.NotLoggedIn, nil)
 	}
+	login := current.Email
 
 	// Find which of our accounts matches this email
-	accounts, err := GetAccounts()
+	var captured *Account
+	err = updateAccounts(func(accounts []Account) ([]Account, error) {
+		for i := range accounts {
+			if accounts[i].Email == login {
+				sessionData, _ := json.Marshal(BuildAuthSession(launcherSettings))
+				accounts[i].LauncherSession = sessionData
+				accounts[i].SessionCaptured = time.Now().Format(time.RFC3339)
+				accounts[i].SessionInvalid = false
+				accounts[i].UnknownFields = launcher.UnknownTokenFields(launcherSettings)
+				captured = &accounts[i]
+				return accounts, nil
+			}
+		}
+		return nil, nil
+	})
 	if err != nil {
-		return
+		return nil, err
 	}
-
-	for i := range accounts {
-		if accounts[i].Email == login {
-			sessionData, _ := json.Marshal(BuildAuthSession(launcherSettings))
-			accounts[i].LauncherSession = sessionData
-			accounts[i].SessionCaptured = time.Now().Format(time.RFC3339)
-			saveAccounts(accounts)
-			return
-		}
+	if captured == nil {
+		return nil, apperror.New(apperror.NoAccountForLogin, nil, "email", config.MaskEmail(login))
 	}
+	return captured, nil
 }
 
-// HasSession checks if an account has a saved session
+// HasSession checks if an account has a saved session that has not been rejected
 func (a *Account) HasSession() bool {
+	if a.SessionInvalid {
+		return false
+	}
 	return (a.LauncherSession != nil && len(a.LauncherSession) > 0) || a.EncryptedSession != ""
 }
 
+// EffectiveCacheTargets returns the cache targets cleared when switching to this account
+func (a *Account) EffectiveCacheTargets() []string {
+	if a.CacheTargets != nil {
+		return *a.CacheTargets
+	}
+	return config.GetSettings().CacheTargets
+}
+
+// SetAccountCacheTargets sets an account's cache target override.
+// nil removes the override so the global list applies again.
+func SetAccountCacheTargets(id string, targets []string) error {
+	return updateAccount(id, func(acc *Account) {
+		if targets == nil {
+			acc.CacheTargets = nil
+		} else {
+			acc.CacheTargets = &targets
+		}
+	})
+}
+
+// SetAccountDefaultGame sets the game selected in the launcher when switching to an account.
+// An empty game keeps whatever was selected when the session was captured.
+func SetAccountDefaultGame(id, game string) error {
+	if game != "" && !launcher.IsValidGame(game) {
+		return apperror.New(apperror.UnknownGame, nil, "game", game)
+	}
+
+	return updateAccount(id, func(acc *Account) {
+		acc.DefaultGame = game
+	})
+}
+
 // BuildAuthSession creates the session map from launcher settings.
-// Single source of truth for which fields to capture.
+// The captured fields are defined by the session schema (config.SessionSchema),
+// which also drives RestoreLauncherSession.
 func BuildAuthSession(launcherSettings map[string]interface{}) map[string]interface{} {
-	return map[string]interface{}{
-		"login":             launcherSettings["login"],
-		"at":                launcherSettings["at"],
-		"rt":                launcherSettings["rt"],
-		"atet":              launcherSettings["atet"],
-		"sysInfCheck":       launcherSettings["sysInfCheck"],
-		"keepLoggedIn":      true,
-		"saveLogin":         true,
-		"selectedGame":      launcherSettings["selectedGame"],
-		"environmentUiType": launcher.ReadEnvironmentUiType(),
-	}
+	return launcher.CaptureSession(launcherSettings)
 }
@@ -0,0 +1,121 @@
+package accounts
+
+import (
+	"log/slog"
+	"os"
+	"path/filepath"
+
+	"tarkov-account-switcher/internal/config"
+	"tarkov-account-switcher/internal/launcher"
+)
+
+// profileDir returns where an account's EFT Settings snapshot is stored
+func profileDir(accountID string) string {
+	return filepath.Join(config.GetPaths().ProfilesDir, accountID, "Settings")
+}
+
+// profileFilter builds the include/exclude filter from the settings
+func profileFilter() launcher.ProfileFilter {
+	settings := config.GetSettings()
+	return launcher.ProfileFilter{
+		Include: settings.ProfileInclude,
+		Exclude: settings.ProfileExclude,
+	}
+}
+
+// removeGameProfile deletes the account's saved game settings, if any.
+// A failure only leaves an orphaned directory behind, so it is logged.
+func removeGameProfile(id string) {
+	if err := os.RemoveAll(filepath.Dir(profileDir(id))); err != nil {
+		slog.Warn("game profile not removed", "account", id, "err", err)
+	}
+}
+
+// SetAccountGameProfile enables or disables the per-account game settings profile.
+// Enabling it snapshots the current EFT settings as the account's starting point
+// if the account is the one currently logged in.
+func SetAccountGameProfile(id string, enabled bool) error {
+	var accounts []Account
+	err := updateAccounts(func(stored []Account) ([]Account, error) {
+		for i := range stored {
+			if stored[i].ID == id {
+				stored[i].GameProfile = enabled
+				if !enabled {
+					removeGameProfile(id)
+				}
+				break
+			}
+		}
+		accounts = stored
+		return stored, nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if enabled {
+		if current := currentLauncherAccount(accounts); current != nil && current.ID == id {
+			return launcher.SaveSettingsProfile(profileDir(id), profileFilter())
+		}
+	}
+	return nil
+}
+
+// saveOutgoingProfile stores the EFT settings of the account currently logged
+// in to the launcher, if it uses a game profile
+func saveOutgoingProfile() error {
+	accounts, err := GetAccounts()
+	if err != nil {
+		return err
+	}
+
+	current := currentLauncherAccount(accounts)
+	if current == nil || !current.GameProfile {
+		return nil
+	}
+
+	return launcher.SaveSettingsProfile(profileDir(current.ID), profileFilter())
+}
+
+// restoreIncomingProfile applies an account's saved EFT settings. Only files
+// that differ are written, and they are snapshotted into tx first so a failed
+// switch puts the previous settings back.
+func restoreIncomingProfile(tx *switchTx, account *Account) error {
+	if !account.GameProfile {
+		return nil
+	}
+
+	dir := profileDir(account.ID)
+	changed, err := launcher.DiffSettingsProfile(dir, profileFilter())
+	if err != nil || len(changed) == 0 {
+		return err
+	}
+
+	snapshot, err := launcher.SnapshotFiles(launcher.GameSettingsFiles(changed)...)
+	if err != nil {
+		return err
+	}
+	tx.onRollback(snapshot.Restore)
+
+	return launcher.ApplySettingsProfile(dir, changed)
+}
+
+// currentLauncherAccount returns the account whose email is logged in to the launcher
+func currentLauncherAccount(accounts []Account) *Account {
+	settings, err := launcher.ReadLauncherSettings()
+	if err != nil {
+		return nil
+	}
+
+	login := launcher.ReadLogin(settings).Email
+	if login == "" {
+		return nil
+	}
+
+	for i := range accounts {
+		if accounts[i].Email == login {
+			return &accounts[i]
+		}
+	}
+	return nil
+}
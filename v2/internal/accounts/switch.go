@@ -0,0 +1,546 @@
+package accounts
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"tarkov-account-switcher/internal/apperror"
+	"tarkov-account-switcher/internal/config"
+	"tarkov-account-switcher/internal/events"
+	"tarkov-account-switcher/internal/hooks"
+	"tarkov-account-switcher/internal/i18n"
+	"tarkov-account-switcher/internal/launcher"
+)
+
+// switchTx tracks how to undo the changes a switch has made so far.
+// Every step that touches launcher state registers its undo action before
+// doing the work; if a later step fails, rollback runs them in reverse order.
+type switchTx struct {
+	ctx       context.Context
+	account   *Account
+	undo      []func() error
+	followUps []func()
+	trace     *Trace
+}
+
+// onRollback registers an undo action
+func (tx *switchTx) onRollback(fn func() error) {
+	tx.undo = append(tx.undo, fn)
+}
+
+// afterSuccess registers work that continues after the switch, such as
+// watching for the login. It only starts once the switch succeeded; a
+// rolled back switch drops it.
+func (tx *switchTx) afterSuccess(fn func()) {
+	tx.followUps = append(tx.followUps, fn)
+}
+
+// rollback runs all registered undo actions, newest first.
+// Errors are collected but do not stop the remaining actions.
+func (tx *switchTx) rollback() error {
+	var firstErr error
+	for i := len(tx.undo) - 1; i >= 0; i-- {
+		if err := tx.undo[i](); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	tx.undo = nil
+	return firstErr
+}
+
+// step ends the running step and announces the one that is about to run.
+// Fails without starting it if the switch was cancelled meanwhile.
+func (tx *switchTx) step(name string) error {
+	tx.endStep(nil)
+	if err := tx.ctx.Err(); err != nil {
+		return apperror.FromContext(err)
+	}
+	slog.Info("switch step", "account", tx.account.ID, "step", name)
+	tx.trace.begin(name)
+
+	event := originEvent(tx.ctx, events.SwitchStep, tx.account)
+	event.Step = name
+	events.Publish(event)
+	return nil
+}
+
+// endStep times the running step and announces that it is done
+func (tx *switchTx) endStep(err error) {
+	step := tx.trace.end(err)
+	if step == nil {
+		return
+	}
+	slog.Debug("switch step done", "account", tx.account.ID, "step", step.Name, "ms", step.DurationMs)
+
+	event := originEvent(tx.ctx, events.SwitchStepDone, tx.account)
+	event.Step = step.Name
+	event.DurationMs = step.DurationMs
+	if err != nil {
+		event.Code = string(apperror.CodeOf(err))
+		event.Error = apperror.Message(err)
+	}
+	events.Publish(event)
+}
+
+// succeed ends the last step and keeps the trace of a completed switch
+func (tx *switchTx) succeed(result *SwitchResult) *SwitchResult {
+	tx.endStep(nil)
+	tx.trace.finish(TraceSucceeded, nil)
+	result.Trace = tx.trace
+	publishSucceeded(tx.ctx, tx.account, result.HasSession, result.Message, tx.trace.DurationMs)
+	for _, fn := range tx.followUps {
+		fn()
+	}
+	return result
+}
+
+// fail rolls back the transaction, runs the switchFailed hooks and builds
+// a failed SwitchResult. Errors outside the apperror catalogue get code.
+func (tx *switchTx) fail(code apperror.Code, err error) *SwitchResult {
+	err = apperror.Wrap(code, err)
+	tx.endStep(err)
+	slog.Error("switch failed", "account", tx.account.ID, "err", err)
+	if rbErr := tx.rollback(); rbErr != nil {
+		slog.Error("switch rollback incomplete", "account", tx.account.ID, "err", rbErr)
+	} else {
+		slog.Info("switch rolled back", "account", tx.account.ID)
+	}
+	hooks.Fire(hookEvent(config.HookSwitchFailed, tx.account, "failed", err.Error()))
+
+	tx.trace.finish(TraceFailed, err)
+	return failedResult(tx.ctx, tx.account, err, tx.trace)
+}
+
+// SwitchAccount switches to the specified account using its default game
+func SwitchAccount(ctx context.Context, id string) *SwitchResult {
+	return SwitchAccountGame(ctx, id, "")
+}
+
+// SwitchAccountGame switches to the specified account and selects game in the launcher.
+// An empty game uses the account's default game. The game is only started once
+// the login is verified if launching after login is enabled and gameLaunchArgs
+// has arguments for it.
+// The launcher settings and Game.ini are snapshotted before anything is
+// modified, and restored if any step fails, so the previous account stays
+// logged in when the switch cannot be completed.
+// If the switch policy requires confirmation and the account was switched
+// into too often, nothing is done and the result has NeedsConfirmation set.
+// Only one account operation runs at a time; a second one fails with
+// apperror.Busy. If ctx ends mid-switch, the switch is rolled back.
+func SwitchAccountGame(ctx context.Context, id, game string) *SwitchResult {
+	return switchAccount(ctx, id, game, false)
+}
+
+// ConfirmSwitchAccountGame is SwitchAccountGame after the user confirmed
+// switching despite the cooldown
+func ConfirmSwitchAccountGame(ctx context.Context, id, game string) *SwitchResult {
+	return switchAccount(ctx, id, game, true)
+}
+
+func switchAccount(ctx context.Context, id, game string, confirmed bool) *SwitchResult {
+	// Not a failed switch: nothing was tried, so no event, hook or trace
+	release, err := beginOperation(ctx, "switch")
+	if err != nil {
+		return &SwitchResult{
+			Success: false,
+			Code:    apperror.CodeOf(err),
+			Error:   apperror.Message(err),
+		}
+	}
+	defer release()
+
+	if game != "" && !launcher.IsValidGame(game) {
+		return rejectSwitch(ctx, nil, apperror.New(apperror.UnknownGame, nil, "game", game))
+	}
+
+	// Get account info before touching the launcher
+	account, err := GetAccountByID(id)
+	if err != nil {
+		return rejectSwitch(ctx, nil, err)
+	}
+	if account == nil {
+		return rejectSwitch(ctx, nil, apperror.New(apperror.AccountNotFound, nil))
+	}
+
+	// Killing the launcher under a running game would log it out mid-raid
+	if launcher.IsGameRunning() {
+		return rejectSwitch(ctx, account, apperror.New(apperror.GameRunning, nil))
+	}
+
+	// An explicit or default game only selects it in the launcher. It is
+	// started only in launch-after-login mode with launcher arguments for it.
+	if game == "" {
+		game = account.DefaultGame
+	}
+	launchGame := config.GetSettings().LaunchGameAfterLogin && config.HasGameLaunchArgs(gameToLaunch(game))
+
+	// Switching into one account too often tends to trigger captchas
+	var warnings []string
+	cooldown := account.Cooldown(time.Now())
+	if cooldown.Exceeded() {
+		if cooldown.Mode == config.PolicyConfirm && !confirmed {
+			slog.Info("switch needs confirmation", "account", account.ID, "switches", cooldown.Switches)
+			return &SwitchResult{
+				Success:           false,
+				AccountName:       account.Name,
+				Email:             account.Email,
+				Error:             cooldown.message(i18n.CooldownConfirm, account.Name),
+				Cooldown:          cooldown,
+				NeedsConfirmation: true,
+			}
+		}
+		warnings = append(warnings, cooldown.message(i18n.CooldownWarning, account.Name))
+	}
+
+	// User hooks may veto the switch before anything is touched.
+	// Cancelling the switch also kills a hook that is still running.
+	if err := hooks.Run(ctx, hookEvent(config.HookBeforeSwitch, account, "", "")); err != nil {
+		if ctx.Err() != nil {
+			return rejectSwitch(ctx, account, err)
+		}
+		return rejectSwitch(ctx, account, apperror.New(apperror.HookVeto, err))
+	}
+
+	slog.Info("switch started", "account", account.ID, "name", account.Name, "game", game, "hasSession", account.HasSession())
+	events.Publish(originEvent(ctx, events.SwitchStarted, account))
+	tx := &switchTx{ctx: ctx, account: account, trace: newTrace(account)}
+
+	// A verification from a previous switch must not see this switch's changes
+	stopVerification()
+
+	// First, save current account session to capture refreshed tokens
+	if err := tx.step("save-session"); err != nil {
+		return tx.fail(apperror.Cancelled, err)
+	}
+	SaveCurrentAccountSession()
+
+	// Re-read the target: when it is the account logged in right now, the
+	// save just stored its rotated tokens and the old ones must not be restored
+	fresh, err := GetAccountByID(id)
+	if err != nil {
+		return tx.fail(apperror.AccountsUnreadable, err)
+	}
+	if fresh == nil {
+		return tx.fail(apperror.AccountNotFound, apperror.New(apperror.AccountNotFound, nil))
+	}
+	account = fresh
+	tx.account = fresh
+
+	// Keep the outgoing account's game settings before the incoming ones replace them
+	if err := saveOutgoingProfile(); err != nil {
+		return tx.fail(apperror.ProfileFailed, err)
+	}
+
+	snapshot, err := launcher.TakeSnapshot()
+	if err != nil {
+		return tx.fail(apperror.SettingsUnreadable, err)
+	}
+	tx.onRollback(snapshot.Restore)
+
+	// Kill launcher; it would overwrite the restored settings on exit
+	if err := tx.step("kill-launcher"); err != nil {
+		return tx.fail(apperror.Cancelled, err)
+	}
+	if err := launcher.KillLauncher(ctx); err != nil {
+		return tx.fail(apperror.KillTimeout, err)
+	}
+
+	// Launcher is closed now, so its cookie database can be copied safely.
+	// Failing to save only loses the refresh, the previous snapshot stays.
+	if err := saveOutgoingCefProfile(); err != nil {
+		slog.Warn("CEF profile not saved", "err", err)
+		warnings = append(warnings, i18n.TF(i18n.WarnCefNotSaved, map[string]string{"error": err.Error()}))
+	}
+
+	// Clear game cache to force fresh data from server (backgrounds, icons, etc.)
+	if err := tx.step("clear-cache"); err != nil {
+		return tx.fail(apperror.Cancelled, err)
+	}
+	cache := launcher.ClearGameCache(account.EffectiveCacheTargets(), false)
+	for _, entry := range cache.Entries {
+		if entry.Error != "" {
+			slog.Warn("cache target not cleared", "path", entry.Path, "err", entry.Error)
+		}
+	}
+	slog.Info("cache cleared", "account", account.ID, "bytes", cache.TotalSize)
+
+	// Restore the incoming account's game settings (only files that differ)
+	if err := tx.step("restore-profiles"); err != nil {
+		return tx.fail(apperror.Cancelled, err)
+	}
+	if err := restoreIncomingProfile(tx, account); err != nil {
+		return tx.fail(apperror.ProfileFailed, err)
+	}
+
+	// Restore the incoming account's launcher cookies and local storage
+	if err := restoreIncomingCefProfile(tx, account); err != nil {
+		return tx.fail(apperror.ProfileFailed, err)
+	}
+
+	// A session that no longer decrypts (e.g. the key was replaced) falls
+	// back to a manual login
+	if account.HasSession() && len(account.LauncherSession) == 0 {
+		slog.Warn("session not decryptable, falling back to manual login", "account", account.ID)
+		warnings = append(warnings, apperror.New(apperror.SessionUndecryptable, nil).Message())
+	}
+
+	// Check if we have a saved session the launcher has not rejected
+	if account.HasSession() && len(account.LauncherSession) > 0 {
+		// Restore session
+		if err := tx.step("restore-session"); err != nil {
+			return tx.fail(apperror.Cancelled, err)
+		}
+		if err := launcher.RestoreLauncherSession(account.LauncherSession, game); err != nil {
+			return tx.fail(apperror.SettingsUnwritable, err)
+		}
+
+		if err := tx.step("start-launcher"); err != nil {
+			return tx.fail(apperror.Cancelled, err)
+		}
+		if err := launcher.StartLauncher(); err != nil {
+			return tx.fail(apperror.LauncherStartFailed, err)
+		}
+
+		// Notify UI to minimize
+		if launcher.OnLauncherStarted != nil {
+			launcher.OnLauncherStarted()
+		}
+		hooks.Fire(hookEvent(config.HookLauncherStarted, account, "auto-login", ""))
+
+		// Confirm the launcher accepts the restored session
+		onLoggedIn := afterLogin(ctx, account, launchGame, game)
+		tx.afterSuccess(func() { startVerification(account, onLoggedIn) })
+		recordSwitch(id, time.Now())
+
+		return tx.succeed(&SwitchResult{
+			Success:     true,
+			AccountName: account.Name,
+			Email:       account.Email,
+			HasSession:  true,
+			Message:     i18n.T(i18n.SwitchAutoLogin),
+			Cache:       cache,
+			Warnings:    warnings,
+			Cooldown:    cooldown,
+		})
+	}
+
+	// No session saved - clear session and start fresh.
+	// The session files about to be deleted belong to the previous account.
+	if err := tx.step("reset-session"); err != nil {
+		return tx.fail(apperror.Cancelled, err)
+	}
+	sessionFiles, err := launcher.DiscoverSessionFiles()
+	if err != nil {
+		return tx.fail(apperror.SettingsUnreadable, err)
+	}
+	filesSnapshot, err := launcher.SnapshotFiles(sessionFiles...)
+	if err != nil {
+		return tx.fail(apperror.SettingsUnreadable, err)
+	}
+	tx.onRollback(filesSnapshot.Restore)
+
+	removed, err := launcher.UpdateLauncherAccount(account.Email)
+	if err != nil {
+		return tx.fail(apperror.SettingsUnwritable, err)
+	}
+	slog.Info("session files removed", "account", account.ID, "files", len(removed.Removed()))
+
+	if err := tx.step("start-launcher"); err != nil {
+		return tx.fail(apperror.Cancelled, err)
+	}
+	if err := launcher.StartLauncher(); err != nil {
+		return tx.fail(apperror.LauncherStartFailed, err)
+	}
+
+	// Notify UI to minimize
+	if launcher.OnLauncherStarted != nil {
+		launcher.OnLauncherStarted()
+	}
+	hooks.Fire(hookEvent(config.HookLauncherStarted, account, "manual-login", ""))
+
+	// Start session watcher after 2 seconds, unless the switch's context
+	// ends first (app shutdown)
+	onLoggedIn := afterLogin(ctx, account, launchGame, game)
+	tx.afterSuccess(func() {
+		go func() {
+			select {
+			case <-ctx.Done():
+				slog.Info("session watcher not started", "account", id, "err", ctx.Err())
+				return
+			case <-time.After(2 * time.Second):
+			}
+			if StartWatcher(id, account.Email) {
+				onLoggedIn()
+			}
+		}()
+	})
+	recordSwitch(id, time.Now())
+
+	return tx.succeed(&SwitchResult{
+		Success:     true,
+		AccountName: account.Name,
+		Email:       account.Email,
+		HasSession:  false,
+		Message:     i18n.T(i18n.SwitchManualLogin),
+		Cache:       cache,
+		Warnings:    warnings,
+		Cooldown:    cooldown,
+
+		SessionFiles: removed,
+	})
+}
+
+// gameToLaunch returns the game started after login: the given one, or EFT
+func gameToLaunch(game string) string {
+	if game == "" {
+		return launcher.GameEFT
+	}
+	return game
+}
+
+// gameStartTimeout is how long the game process may take to appear after
+// the launcher was asked to start it
+const gameStartTimeout = 90 * time.Second
+
+// afterLogin returns the action to run once the login is verified: the
+// loggedIn hooks and, if requested, starting the game. Nothing runs once
+// the switch's context has ended.
+// Without an explicit or default game EFT is started.
+func afterLogin(ctx context.Context, account *Account, launchGame bool, game string) func() {
+	game = gameToLaunch(game)
+	return func() {
+		if ctx.Err() != nil {
+			slog.Info("after-login actions skipped", "account", account.ID, "err", ctx.Err())
+			return
+		}
+		hooks.Fire(hookEvent(config.HookLoggedIn, account, "verified", ""))
+		if launchGame {
+			go startGame(ctx, account, game)
+		}
+	}
+}
+
+// startGame starts the game through the launcher and waits for its process.
+// If it cannot be started or does not appear, a game.not_started event
+// reports why. Waiting stops silently when ctx ends.
+func startGame(ctx context.Context, account *Account, game string) {
+	err := launcher.StartGame(game)
+	if err == nil {
+		deadline := time.Now().Add(gameStartTimeout)
+		for !launcher.IsGameRunning() {
+			if time.Now().After(deadline) {
+				err = apperror.New(apperror.GameNotStarted, nil, "game", game)
+				break
+			}
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(2 * time.Second):
+			}
+		}
+	}
+	if err != nil {
+		slog.Warn("game not started", "account", account.ID, "game", game, "err", err)
+		event := switchEvent(events.GameNotStarted, account)
+		event.Game = game
+		event.Code = string(apperror.CodeOf(err))
+		event.Error = apperror.Message(err)
+		events.Publish(event)
+		return
+	}
+	slog.Info("game started", "account", account.ID, "game", game)
+}
+
+// switchEvent builds an event about an account
+func switchEvent(eventType string, account *Account) events.Event {
+	event := events.Event{Type: eventType}
+	if account != nil {
+		event.AccountID = account.ID
+		event.AccountName = account.Name
+	}
+	return event
+}
+
+// originEvent is switchEvent for the events of a switch, tagged with the
+// origin of ctx
+func originEvent(ctx context.Context, eventType string, account *Account) events.Event {
+	event := switchEvent(eventType, account)
+	event.Origin = originOf(ctx)
+	return event
+}
+
+// originKey is the context key of a switch's origin
+type originKey struct{}
+
+// Switch origins other than the app window
+const (
+	OriginAction = "action" // tray, shortcut or tarkovswitch:// link
+	OriginAPI    = "api"
+)
+
+// WithOrigin marks switches run with ctx as started from origin. Their
+// events carry it, so e.g. the app does not notify twice about a failure
+// it already reports itself.
+func WithOrigin(ctx context.Context, origin string) context.Context {
+	return context.WithValue(ctx, originKey{}, origin)
+}
+
+func originOf(ctx context.Context) string {
+	origin, _ := ctx.Value(originKey{}).(string)
+	return origin
+}
+
+// rejectSwitch fails a switch before anything was changed
+func rejectSwitch(ctx context.Context, account *Account, err error) *SwitchResult {
+	if account != nil {
+		slog.Warn("switch rejected", "account", account.ID, "reason", err)
+	} else {
+		slog.Warn("switch rejected", "reason", err)
+	}
+
+	trace := newTrace(account)
+	trace.finish(TraceRejected, err)
+	return failedResult(ctx, account, err, trace)
+}
+
+// failedResult publishes switch.failed and builds the failed SwitchResult
+// with the error's code and localized message
+func failedResult(ctx context.Context, account *Account, err error, trace *Trace) *SwitchResult {
+	code, message := apperror.CodeOf(err), apperror.Message(err)
+
+	event := originEvent(ctx, events.SwitchFailed, account)
+	event.Code = string(code)
+	event.Error = message
+	event.DurationMs = trace.DurationMs
+	events.Publish(event)
+
+	return &SwitchResult{
+		Success: false,
+		Code:    code,
+		Error:   message,
+		Trace:   trace,
+	}
+}
+
+// publishSucceeded announces a completed switch
+func publishSucceeded(ctx context.Context, account *Account, hasSession bool, message string, durationMs int64) {
+	slog.Info("switch succeeded", "account", account.ID, "hasSession", hasSession, "ms", durationMs)
+
+	event := originEvent(ctx, events.SwitchSucceeded, account)
+	event.HasSession = hasSession
+	event.Message = message
+	event.DurationMs = durationMs
+	events.Publish(event)
+}
+
+// hookEvent builds the hook event for an account
+func hookEvent(name string, account *Account, result, errMsg string) hooks.Event {
+	event := hooks.Event{Name: name, Result: result, Error: errMsg}
+	if account != nil {
+		event.AccountID = account.ID
+		event.AccountName = account.Name
+		event.Email = account.Email
+	}
+	return event
+}
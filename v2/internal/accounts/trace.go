@@ -0,0 +1,140 @@
+package accounts
+
+import (
+	"encoding/json"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"tarkov-account-switcher/internal/apperror"
+	"tarkov-account-switcher/internal/config"
+)
+
+// maxTraces is how many switch traces TracesFile keeps
+const maxTraces = 20
+
+var traceMutex sync.Mutex
+
+// TraceStep is one timed step of a switch
+type TraceStep struct {
+	Name       string    `json:"name"`
+	Started    time.Time `json:"started"`
+	DurationMs int64     `json:"durationMs"`
+	Error      string    `json:"error,omitempty"`
+}
+
+// Trace records how one switch went, step by step. The last traces are kept
+// in the log directory for support bundles.
+type Trace struct {
+	AccountID   string      `json:"accountId,omitempty"`
+	AccountName string      `json:"accountName,omitempty"`
+	Started     time.Time   `json:"started"`
+	DurationMs  int64       `json:"durationMs"`
+	Result      string      `json:"result"` // succeeded, failed or rejected
+	Code        string      `json:"code,omitempty"`
+	Error       string      `json:"error,omitempty"`
+	Steps       []TraceStep `json:"steps"`
+
+	running bool // the last step has not ended yet
+}
+
+// Trace results
+const (
+	TraceSucceeded = "succeeded"
+	TraceFailed    = "failed"
+	TraceRejected  = "rejected" // refused before anything was changed
+)
+
+func newTrace(account *Account) *Trace {
+	t := &Trace{Started: time.Now(), Steps: []TraceStep{}}
+	if account != nil {
+		t.AccountID = account.ID
+		t.AccountName = account.Name
+	}
+	return t
+}
+
+// begin starts a step
+func (t *Trace) begin(name string) {
+	t.Steps = append(t.Steps, TraceStep{Name: name, Started: time.Now()})
+	t.running = true
+}
+
+// end finishes the running step and returns it, or nil if none is running
+func (t *Trace) end(err error) *TraceStep {
+	if !t.running {
+		return nil
+	}
+	t.running = false
+	step := &t.Steps[len(t.Steps)-1]
+	step.DurationMs = time.Since(step.Started).Milliseconds()
+	if err != nil {
+		step.Error = err.Error()
+	}
+	return step
+}
+
+// finish records the outcome and keeps the trace in TracesFile
+func (t *Trace) finish(result string, err error) {
+	t.DurationMs = time.Since(t.Started).Milliseconds()
+	t.Result = result
+	if err != nil {
+		t.Code = string(apperror.CodeOf(err))
+		t.Error = err.Error()
+	}
+	if err := saveTrace(t); err != nil {
+		slog.Warn("switch trace not saved", "err", err)
+	}
+}
+
+// TracesFile returns the file holding the last switch traces
+func TracesFile() string {
+	return filepath.Join(config.GetPaths().LogDir, "switch-traces.json")
+}
+
+// RecentTraces returns the kept switch traces, newest first
+func RecentTraces() ([]Trace, error) {
+	traceMutex.Lock()
+	defer traceMutex.Unlock()
+	return loadTraces()
+}
+
+func loadTraces() ([]Trace, error) {
+	data, err := os.ReadFile(TracesFile())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return []Trace{}, nil
+		}
+		return nil, err
+	}
+	var traces []Trace
+	if err := json.Unmarshal(data, &traces); err != nil {
+		return nil, err
+	}
+	return traces, nil
+}
+
+func saveTrace(t *Trace) error {
+	traceMutex.Lock()
+	defer traceMutex.Unlock()
+
+	traces, err := loadTraces()
+	if err != nil {
+		traces = nil // start over rather than lose new traces to a broken file
+	}
+	traces = append([]Trace{*t}, traces...)
+	if len(traces) > maxTraces {
+		traces = traces[:maxTraces]
+	}
+
+	data, err := json.MarshalIndent(traces, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(TracesFile()), 0700); err != nil {
+		return err
+	}
+	return os.WriteFile(TracesFile(), data, 0600)
+}
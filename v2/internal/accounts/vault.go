@@ -0,0 +1,110 @@
+package accounts
+
+import (
+	"log/slog"
+	"os"
+	"path/filepath"
+
+	"tarkov-account-switcher/internal/config"
+	"tarkov-account-switcher/internal/launcher"
+)
+
+// vaultPath returns the encrypted CEF profile file of an account
+func vaultPath(accountID string) string {
+	return filepath.Join(config.GetPaths().VaultDir, accountID+".cef")
+}
+
+// writeVault encrypts and stores an account's CEF profile archive
+func writeVault(accountID string, archive []byte) error {
+	encrypted, err := Encrypt(string(archive))
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(config.GetPaths().VaultDir, 0700); err != nil {
+		return err
+	}
+	return os.WriteFile(vaultPath(accountID), []byte(encrypted), 0600)
+}
+
+// readVault loads and decrypts an account's CEF profile archive.
+// Returns nil without error if the account has no snapshot yet.
+func readVault(accountID string) ([]byte, error) {
+	data, err := os.ReadFile(vaultPath(accountID))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	decrypted, err := Decrypt(string(data))
+	if err != nil {
+		return nil, err
+	}
+	return []byte(decrypted), nil
+}
+
+// removeCefVault deletes the account's CEF profile snapshot, if any.
+// A failure only leaves an orphaned file behind, so it is logged.
+func removeCefVault(id string) {
+	if err := os.Remove(vaultPath(id)); err != nil && !os.IsNotExist(err) {
+		slog.Warn("cef profile not removed", "account", id, "err", err)
+	}
+}
+
+// SetAccountCefProfile enables or disables the launcher CEF profile snapshot for an account
+func SetAccountCefProfile(id string, enabled bool) error {
+	return updateAccount(id, func(acc *Account) {
+		acc.CefProfile = enabled
+		if !enabled {
+			removeCefVault(id)
+		}
+	})
+}
+
+// saveOutgoingCefProfile snapshots the launcher's cookies and local storage for
+// the account currently logged in. Must run after the launcher has been killed.
+func saveOutgoingCefProfile() error {
+	accounts, err := GetAccounts()
+	if err != nil {
+		return err
+	}
+
+	current := currentLauncherAccount(accounts)
+	if current == nil || !current.CefProfile {
+		return nil
+	}
+
+	archive, err := launcher.CollectCefProfile(launcher.DefaultCefProfileFilter, config.GetSettings().CefProfileMaxBytes)
+	if err != nil {
+		return err
+	}
+	return writeVault(current.ID, archive)
+}
+
+// restoreIncomingCefProfile writes an account's stored CEF profile back into the
+// launcher, snapshotting every file it touches into tx first
+func restoreIncomingCefProfile(tx *switchTx, account *Account) error {
+	if !account.CefProfile {
+		return nil
+	}
+
+	archive, err := readVault(account.ID)
+	if err != nil || archive == nil {
+		return err
+	}
+
+	filter := launcher.DefaultCefProfileFilter
+	files, err := launcher.CefProfileFiles(archive, filter)
+	if err != nil {
+		return err
+	}
+
+	snapshot, err := launcher.SnapshotFiles(files...)
+	if err != nil {
+		return err
+	}
+	tx.onRollback(snapshot.Restore)
+
+	return launcher.RestoreCefProfile(archive, filter, config.GetSettings().CefProfileMaxBytes)
+}
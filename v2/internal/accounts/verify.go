@@ -0,0 +1,143 @@
+package accounts
+
+import (
+	"encoding/json"
+	"log/slog"
+	"sync"
+	"time"
+
+	"tarkov-account-switcher/internal/config"
+	"tarkov-account-switcher/internal/i18n"
+	"tarkov-account-switcher/internal/launcher"
+)
+
+// VerifyResult is the outcome of watching the launcher after a switch
+type VerifyResult struct {
+	AccountID   string
+	AccountName string
+	Email       string
+	Accepted    bool
+	Message     string
+}
+
+var (
+	verifyMutex sync.Mutex
+	verifyStop  chan struct{}
+
+	// SessionVerifiedCallback is called when post-switch verification finishes
+	SessionVerifiedCallback func(result VerifyResult)
+)
+
+// startVerification watches the launcher for the configured window and
+// reports whether it kept the restored session. Any verification still
+// running from a previous switch is cancelled. onLoggedIn runs once the
+// login is confirmed, either directly or after a re-login was captured.
+func startVerification(account *Account, onLoggedIn func()) {
+	window := time.Duration(config.GetSettings().VerifySeconds) * time.Second
+	if window <= 0 {
+		if onLoggedIn != nil {
+			go onLoggedIn()
+		}
+		return
+	}
+
+	stopVerification()
+
+	verifyMutex.Lock()
+	verifyStop = make(chan struct{})
+	localStop := verifyStop
+	verifyMutex.Unlock()
+
+	var restored map[string]interface{}
+	json.Unmarshal(account.LauncherSession, &restored)
+	restoredAT, _ := restored[config.SessionAccessToken].(string)
+
+	go func() {
+		accepted, ok := verifySession(account.Email, restoredAT, window, localStop)
+		if !ok {
+			return // superseded by a newer switch
+		}
+
+		verifyMutex.Lock()
+		if verifyStop == localStop {
+			verifyStop = nil
+		}
+		verifyMutex.Unlock()
+
+		result := VerifyResult{
+			AccountID:   account.ID,
+			AccountName: account.Name,
+			Email:       account.Email,
+			Accepted:    accepted,
+		}
+		if accepted {
+			slog.Info("restored session accepted", "account", account.ID)
+			result.Message = i18n.TF(i18n.VerifyLoggedIn, map[string]string{"name": account.Name})
+		} else {
+			slog.Warn("restored session rejected", "account", account.ID)
+			result.Message = i18n.T(i18n.VerifyRejected)
+			if err := MarkSessionInvalid(account.ID); err != nil {
+				slog.Error("session not marked invalid", "account", account.ID, "err", err)
+			}
+		}
+
+		if SessionVerifiedCallback != nil {
+			SessionVerifiedCallback(result)
+		}
+
+		// Session was dropped - wait for the user to log in again
+		if !accepted {
+			accepted = StartWatcher(account.ID, account.Email)
+		}
+
+		if accepted && onLoggedIn != nil {
+			onLoggedIn()
+		}
+	}()
+}
+
+// stopVerification cancels a running verification, e.g. because another
+// switch is about to rewrite the launcher settings it is watching.
+func stopVerification() {
+	verifyMutex.Lock()
+	defer verifyMutex.Unlock()
+
+	if verifyStop != nil {
+		close(verifyStop)
+		verifyStop = nil
+	}
+}
+
+// verifySession polls the launcher settings until the session is either
+// refreshed (accepted), dropped (rejected) or the window ends. If the window
+// ends with the restored tokens still in place, the session counts as accepted.
+// ok is false when verification was cancelled.
+func verifySession(expectedEmail, restoredAT string, window time.Duration, stop <-chan struct{}) (accepted bool, ok bool) {
+	ticker := time.NewTicker(1 * time.Second)
+	defer ticker.Stop()
+	deadline := time.After(window)
+
+	for {
+		select {
+		case <-stop:
+			return false, false
+
+		case <-deadline:
+			return true, true
+
+		case <-ticker.C:
+			settings, err := launcher.ReadLauncherSettings()
+			if err != nil {
+				continue // launcher may be rewriting the file
+			}
+
+			login := launcher.ReadLogin(settings)
+			if login.Email != expectedEmail || !login.HasTokens() {
+				return false, true
+			}
+			if login.AccessToken != restoredAT {
+				return true, true // launcher refreshed the token
+			}
+		}
+	}
+}
@@ -2,11 +2,16 @@ package accounts
 
 import (
 	"encoding/json"
+	"log/slog"
 	"os"
+	"strings"
 	"sync"
 	"time"
 
 	"tarkov-account-switcher/internal/config"
+	"tarkov-account-switcher/internal/events"
+	"tarkov-account-switcher/internal/hooks"
+	"tarkov-account-switcher/internal/launcher"
 )
 
 var (
@@ -19,8 +24,10 @@ var (
 	SessionCapturedCallback func(accountID string)
 )
 
-// StartWatcher starts watching for session tokens
-func StartWatcher(accountID, expectedEmail string) {
+// StartWatcher starts watching for session tokens.
+// It blocks until a session is captured (true), or the watcher times out
+// or is stopped (false).
+func StartWatcher(accountID, expectedEmail string) bool {
 	watcherMutex.Lock()
 
 	// Stop any existing watcher
@@ -35,7 +42,8 @@ func StartWatcher(accountID, expectedEmail string) {
 
 	watcherMutex.Unlock()
 
-	paths := config.GetPaths()
+	settingsPath := config.LauncherSettingsPath()
+	slog.Info("watcher started", "account", accountID)
 	ticker := time.NewTicker(2 * time.Second)
 	timeout := time.After(5 * time.Minute)
 
@@ -44,7 +52,8 @@ func StartWatcher(accountID, expectedEmail string) {
 	for {
 		select {
 		case <-localStopChan:
-			return
+			slog.Info("watcher stopped", "account", accountID)
+			return false
 
 		case <-timeout:
 			watcherMutex.Lock()
@@ -53,34 +62,44 @@ func StartWatcher(accountID, expectedEmail string) {
 				watcherAccountID = ""
 			}
 			watcherMutex.Unlock()
-			return
+
+			slog.Warn("watcher timed out without a login", "account", accountID)
+			account, _ := GetAccountByID(accountID)
+			events.Publish(switchEvent(events.WatcherTimeout, account))
+			return false
 
 		case <-ticker.C:
-			data, err := os.ReadFile(paths.LauncherSettingsPath)
+			data, err := os.ReadFile(settingsPath)
 			if err != nil {
+				slog.Debug("launcher settings not readable", "path", settingsPath, "err", err)
 				continue
 			}
 
 			var launcherSettings map[string]interface{}
 			if err := json.Unmarshal(data, &launcherSettings); err != nil {
+				// The launcher may be halfway through writing the file
+				slog.Debug("launcher settings not parsable", "path", settingsPath, "err", err)
 				continue
 			}
 
 			// Check if user logged in with correct email and has session tokens
-			login, _ := launcherSettings["login"].(string)
-			at, _ := launcherSettings["at"].(string)
-			rt, _ := launcherSettings["rt"].(string)
-
-			if login == expectedEmail && at != "" && rt != "" {
+			if login := launcher.ReadLogin(launcherSettings); login.Email == expectedEmail && login.HasTokens() {
 				// Session detected - capture auth fields
 				sessionData, err := json.Marshal(BuildAuthSession(launcherSettings))
 				if err != nil {
+					slog.Error("session not encodable", "account", accountID, "err", err)
 					continue
 				}
 
-				if err := UpdateAccountSession(accountID, sessionData); err != nil {
+				unknown := launcher.UnknownTokenFields(launcherSettings)
+				if len(unknown) > 0 {
+					slog.Warn("launcher has token fields outside the session schema", "account", accountID, "fields", strings.Join(unknown, ","))
+				}
+				if err := UpdateAccountSession(accountID, sessionData, unknown); err != nil {
+					slog.Error("session not saved", "account", accountID, "err", err)
 					continue
 				}
+				slog.Info("session captured", "account", accountID)
 
 				// Stop watcher
 				watcherMutex.Lock()
@@ -95,7 +114,12 @@ func StartWatcher(accountID, expectedEmail string) {
 					SessionCapturedCallback(accountID)
 				}
 
-				return
+				if account, err := GetAccountByID(accountID); err == nil && account != nil {
+					events.Publish(switchEvent(events.SessionCaptured, account))
+					hooks.Fire(hookEvent(config.HookSessionCaptured, account, "captured", ""))
+				}
+
+				return true
 			}
 		}
 	}
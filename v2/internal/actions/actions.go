@@ -0,0 +1,139 @@
+// Package actions parses the requests that shortcuts and other programs hand
+// to the switcher, e.g. "TarkovAccountSwitcher.exe --switch Main".
+package actions
+
+import (
+	"net/url"
+	"strings"
+
+	"tarkov-account-switcher/internal/accounts"
+	"tarkov-account-switcher/internal/apperror"
+	"tarkov-account-switcher/internal/config"
+	"tarkov-account-switcher/internal/launcher"
+)
+
+// Action kinds
+const (
+	KindSwitch = "switch"
+)
+
+// Action is a request to run in the switcher
+type Action struct {
+	Kind    string
+	Account string // account name, ID or prefix (see accounts.FindAccount)
+	Game    string // optional game to select in the launcher
+}
+
+// ParseArgs looks for an action in command-line arguments.
+// It returns nil without error if the arguments hold no action, so a plain
+// start (or one with unrelated flags) is not treated as a request.
+// Supported: --switch <account> [--game eft|arena], also as --switch=<account>,
+// and a single tarkovswitch:// link as passed by the URI handler.
+func ParseArgs(args []string) (*Action, error) {
+	var action *Action
+	var game string
+
+	for _, arg := range args {
+		if isURI(arg) {
+			if len(args) != 1 {
+				return nil, apperror.New(apperror.InvalidArguments, nil, "arg", "link")
+			}
+			return ParseURI(arg)
+		}
+	}
+
+	for i := 0; i < len(args); i++ {
+		name, value, hasValue := strings.Cut(args[i], "=")
+		if name != "--switch" && name != "--game" {
+			continue
+		}
+		if !hasValue {
+			if i+1 >= len(args) {
+				return nil, apperror.New(apperror.InvalidArguments, nil, "arg", name)
+			}
+			i++
+			value = args[i]
+		}
+
+		switch name {
+		case "--switch":
+			action = &Action{Kind: KindSwitch, Account: strings.TrimSpace(value)}
+		case "--game":
+			game = value
+		}
+	}
+
+	if action == nil {
+		if game != "" {
+			return nil, apperror.New(apperror.InvalidArguments, nil, "arg", "--switch")
+		}
+		return nil, nil
+	}
+	if action.Account == "" {
+		return nil, apperror.New(apperror.InvalidArguments, nil, "arg", "--switch")
+	}
+	if game != "" && !launcher.IsValidGame(game) {
+		return nil, apperror.New(apperror.UnknownGame, nil, "game", game)
+	}
+	action.Game = game
+	return action, nil
+}
+
+// SwitchURI returns the link that switches to the given account
+func SwitchURI(accountID string) string {
+	return config.URIScheme + "://" + KindSwitch + "/" + url.PathEscape(accountID)
+}
+
+// isURI reports whether arg looks like a tarkovswitch:// link
+func isURI(arg string) bool {
+	return len(arg) > len(config.URIScheme) &&
+		strings.EqualFold(arg[:len(config.URIScheme)+1], config.URIScheme+":")
+}
+
+// ParseURI parses a tarkovswitch://switch/<id>[?game=eft|arena] link.
+// Links come from untrusted places (web pages, other programs), so anything
+// but a known action for an existing account ID is rejected.
+func ParseURI(raw string) (*Action, error) {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return nil, apperror.New(apperror.InvalidLink, nil)
+	}
+	if !strings.EqualFold(u.Scheme, config.URIScheme) || u.User != nil || u.Fragment != "" {
+		return nil, apperror.New(apperror.InvalidLink, nil)
+	}
+	if u.Host != KindSwitch {
+		return nil, apperror.New(apperror.InvalidLink, nil)
+	}
+
+	// Windows may append a trailing slash to the link
+	id := strings.TrimSuffix(strings.TrimPrefix(u.Path, "/"), "/")
+	if !validID(id) {
+		return nil, apperror.New(apperror.InvalidLink, nil)
+	}
+	account, err := accounts.GetAccountByID(id)
+	if err != nil || account == nil {
+		return nil, apperror.New(apperror.AccountNotFound, nil)
+	}
+
+	action := &Action{Kind: KindSwitch, Account: account.ID}
+	for key, values := range u.Query() {
+		if key != "game" || len(values) != 1 || !launcher.IsValidGame(values[0]) {
+			return nil, apperror.New(apperror.InvalidLink, nil)
+		}
+		action.Game = values[0]
+	}
+	return action, nil
+}
+
+// validID reports whether id only uses characters of generated account IDs
+func validID(id string) bool {
+	if id == "" || len(id) > 64 {
+		return false
+	}
+	for _, r := range id {
+		if !(r >= '0' && r <= '9' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r == '-' || r == '_') {
+			return false
+		}
+	}
+	return true
+}
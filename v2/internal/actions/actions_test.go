@@ -0,0 +1,77 @@
+package actions
+
+import (
+	"os"
+	"reflect"
+	"testing"
+
+	"tarkov-account-switcher/internal/apperror"
+	"tarkov-account-switcher/internal/config"
+)
+
+// TestMain keeps the tests away from the real data directory and saves the
+// account the links point to
+func TestMain(m *testing.M) {
+	dir, err := os.MkdirTemp("", "tarkov-switcher-test-")
+	if err != nil {
+		panic(err)
+	}
+	os.Setenv(config.DataDirEnv, dir)
+	if err := config.EnsureDataDir(); err != nil {
+		panic(err)
+	}
+	accounts := `[{"id":"k3j9x","name":"Main","email":"main@example.com"}]`
+	if err := os.WriteFile(config.GetPaths().AccountsFile, []byte(accounts), 0600); err != nil {
+		panic(err)
+	}
+
+	code := m.Run()
+	os.RemoveAll(dir)
+	os.Exit(code)
+}
+
+func TestParseArgs(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+		want *Action
+		code apperror.Code
+	}{
+		{"plain start", nil, nil, ""},
+		{"unrelated flags", []string{"--minimized", "-v"}, nil, ""},
+		{"switch", []string{"--switch", "Main"}, &Action{Kind: KindSwitch, Account: "Main"}, ""},
+		{"switch with =", []string{"--switch=Main"}, &Action{Kind: KindSwitch, Account: "Main"}, ""},
+		{"game after", []string{"--switch", "Main", "--game", "arena"}, &Action{Kind: KindSwitch, Account: "Main", Game: "arena"}, ""},
+		{"game before", []string{"--game=eft", "--switch", "Main"}, &Action{Kind: KindSwitch, Account: "Main", Game: "eft"}, ""},
+		{"account is trimmed", []string{"--switch", " Main "}, &Action{Kind: KindSwitch, Account: "Main"}, ""},
+		{"switch without value", []string{"--switch"}, nil, apperror.InvalidArguments},
+		{"empty account", []string{"--switch="}, nil, apperror.InvalidArguments},
+		{"game without switch", []string{"--game", "eft"}, nil, apperror.InvalidArguments},
+		{"game without value", []string{"--switch", "Main", "--game"}, nil, apperror.InvalidArguments},
+		{"unknown game", []string{"--switch", "Main", "--game", "pubg"}, nil, apperror.UnknownGame},
+		{"link", []string{"tarkovswitch://switch/k3j9x"}, &Action{Kind: KindSwitch, Account: "k3j9x"}, ""},
+		{"link with game", []string{"tarkovswitch://switch/k3j9x/?game=arena"}, &Action{Kind: KindSwitch, Account: "k3j9x", Game: "arena"}, ""},
+		{"link scheme in other case", []string{"TarkovSwitch://switch/k3j9x"}, &Action{Kind: KindSwitch, Account: "k3j9x"}, ""},
+		{"link next to other args", []string{"--switch", "Main", "tarkovswitch://switch/k3j9x"}, nil, apperror.InvalidArguments},
+		{"link with unknown action", []string{"tarkovswitch://delete/k3j9x"}, nil, apperror.InvalidLink},
+		{"link by name", []string{"tarkovswitch://switch/Main"}, nil, apperror.AccountNotFound},
+		{"link to unknown account", []string{"tarkovswitch://switch/nope"}, nil, apperror.AccountNotFound},
+		{"link with bad id", []string{"tarkovswitch://switch/..%2F..%2Fx"}, nil, apperror.InvalidLink},
+		{"link with unknown parameter", []string{"tarkovswitch://switch/k3j9x?run=calc"}, nil, apperror.InvalidLink},
+		{"link with unknown game", []string{"tarkovswitch://switch/k3j9x?game=pubg"}, nil, apperror.InvalidLink},
+		{"link with two games", []string{"tarkovswitch://switch/k3j9x?game=eft&game=arena"}, nil, apperror.InvalidLink},
+		{"link with user", []string{"tarkovswitch://me@switch/k3j9x"}, nil, apperror.InvalidLink},
+		{"link with fragment", []string{"tarkovswitch://switch/k3j9x#x"}, nil, apperror.InvalidLink},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseArgs(tt.args)
+			if code := apperror.CodeOf(err); code != tt.code {
+				t.Fatalf("ParseArgs(%q) error = %v, want code %q", tt.args, err, tt.code)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseArgs(%q) = %+v, want %+v", tt.args, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,272 @@
+// Package api serves an opt-in HTTP/JSON API on 127.0.0.1 for automation
+// (Stream Deck, AutoHotkey, Home Assistant). Every request needs the bearer
+// token stored in the data directory.
+package api
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"net"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"tarkov-account-switcher/internal/accounts"
+	"tarkov-account-switcher/internal/apperror"
+	"tarkov-account-switcher/internal/config"
+	"tarkov-account-switcher/internal/service"
+)
+
+// maxBodyBytes limits request bodies; requests are tiny JSON objects
+const maxBodyBytes = 4 << 10
+
+var (
+	mu       sync.Mutex
+	server   *http.Server
+	listener net.Listener // closed by Stop itself, Serve may not have taken it yet
+	token    string
+	startErr error           // why the last Start failed
+	baseCtx  context.Context // switches run under it, not under their request
+
+	// OnSwitched is called after a switch was requested over the API
+	OnSwitched func(result service.SwitchResultDTO)
+)
+
+// Start starts the API on the configured port, restarting it if it runs.
+// Switches requested over the API run under ctx, so they end with the app
+// rather than with the HTTP client. A failure is also kept for LastError.
+func Start(ctx context.Context) error {
+	Stop()
+
+	err := start(ctx)
+	mu.Lock()
+	startErr = err
+	mu.Unlock()
+	return err
+}
+
+func start(ctx context.Context) error {
+	tok, err := Token()
+	if err != nil {
+		return err
+	}
+
+	port := config.GetSettings().API.Port
+	l, err := net.Listen("tcp", "127.0.0.1:"+strconv.Itoa(port))
+	if err != nil {
+		return err
+	}
+
+	srv := &http.Server{
+		Handler:           newHandler(port),
+		ReadHeaderTimeout: 5 * time.Second,
+	}
+
+	mu.Lock()
+	server = srv
+	listener = l
+	token = tok
+	baseCtx = ctx
+	mu.Unlock()
+
+	go srv.Serve(l)
+	return nil
+}
+
+// Stop shuts the API down if it runs. Event streams are closed too;
+// the server does not track hijacked WebSocket connections.
+func Stop() {
+	mu.Lock()
+	srv, l := server, listener
+	server, listener = nil, nil
+	startErr = nil
+	mu.Unlock()
+
+	if srv != nil {
+		srv.Close()
+		l.Close() // frees the port before a restart binds it again
+	}
+	closeStreams("API stopped")
+}
+
+// LastError returns why the last Start failed, nil if it succeeded or the
+// API was stopped since
+func LastError() error {
+	mu.Lock()
+	defer mu.Unlock()
+	return startErr
+}
+
+// IsRunning reports whether the API is serving
+func IsRunning() bool {
+	mu.Lock()
+	defer mu.Unlock()
+	return server != nil
+}
+
+// Token returns the bearer token, creating it on first use
+func Token() (string, error) {
+	data, err := os.ReadFile(config.GetPaths().APITokenFile)
+	if err == nil && len(strings.TrimSpace(string(data))) > 0 {
+		return strings.TrimSpace(string(data)), nil
+	}
+	if err != nil && !os.IsNotExist(err) {
+		return "", err
+	}
+	return RegenerateToken()
+}
+
+// RegenerateToken replaces the bearer token; clients using the old one are
+// rejected and open event streams are closed
+func RegenerateToken() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	tok := hex.EncodeToString(buf)
+
+	if err := os.WriteFile(config.GetPaths().APITokenFile, []byte(tok), 0600); err != nil {
+		return "", err
+	}
+
+	mu.Lock()
+	token = tok
+	mu.Unlock()
+	closeStreams("token regenerated")
+	return tok, nil
+}
+
+// newHandler builds the API routes behind the host and token checks
+func newHandler(port int) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /v1/accounts", handleAccounts)
+	mux.HandleFunc("POST /v1/switch", handleSwitch)
+	mux.HandleFunc("GET /v1/watcher", handleWatcher)
+	mux.HandleFunc("GET /v1/login", handleLogin)
+	mux.HandleFunc("GET /v1/events", handleEvents)
+
+	hosts := map[string]bool{
+		"127.0.0.1:" + strconv.Itoa(port): true,
+		"localhost:" + strconv.Itoa(port): true,
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Reject other host names so DNS rebinding cannot reach the API
+		if !hosts[r.Host] {
+			writeError(w, http.StatusForbidden, errors.New("invalid host"))
+			return
+		}
+		if !authorized(r) {
+			writeError(w, http.StatusUnauthorized, errors.New("missing or invalid token"))
+			return
+		}
+		r.Body = http.MaxBytesReader(w, r.Body, maxBodyBytes)
+		mux.ServeHTTP(w, r)
+	})
+}
+
+// authorized checks the bearer token in constant time. The event stream also
+// takes it as ?token= because browser WebSockets cannot set headers.
+func authorized(r *http.Request) bool {
+	got, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !ok && r.URL.Path == "/v1/events" {
+		got = r.URL.Query().Get("token")
+		ok = got != ""
+	}
+	if !ok {
+		return false
+	}
+
+	mu.Lock()
+	want := token
+	mu.Unlock()
+
+	return want != "" && subtle.ConstantTimeCompare([]byte(got), []byte(want)) == 1
+}
+
+func handleAccounts(w http.ResponseWriter, r *http.Request) {
+	dtos, err := service.ListAccounts()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, dtos)
+}
+
+// switchRequest is the body of POST /v1/switch
+type switchRequest struct {
+	Account string `json:"account"` // ID, name or unambiguous prefix
+	Game    string `json:"game"`    // optional: eft or arena
+	Confirm bool   `json:"confirm"` // switch despite a confirm switch policy
+}
+
+func handleSwitch(w http.ResponseWriter, r *http.Request) {
+	var req switchRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, errors.New("invalid request body"))
+		return
+	}
+
+	account, err := accounts.FindAccount(req.Account)
+	if err != nil {
+		writeError(w, http.StatusNotFound, err)
+		return
+	}
+
+	// A client that gives up does not roll the switch back; it still runs
+	// to the end and is reported as a notification
+	mu.Lock()
+	ctx := baseCtx
+	mu.Unlock()
+	if ctx == nil {
+		ctx = context.WithoutCancel(r.Context())
+	}
+	ctx = accounts.WithOrigin(ctx, accounts.OriginAPI)
+	result := service.Switch(ctx, account.ID, req.Game, req.Confirm)
+	if OnSwitched != nil {
+		OnSwitched(result)
+	}
+
+	status := http.StatusOK
+	switch {
+	case result.NeedsConfirmation, result.ErrorCode == string(apperror.Busy):
+		status = http.StatusConflict
+	case !result.Success:
+		status = http.StatusInternalServerError
+	}
+	writeJSON(w, status, result)
+}
+
+func handleWatcher(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, service.WatcherStatus())
+}
+
+func handleLogin(w http.ResponseWriter, r *http.Request) {
+	login, err := service.CurrentLogin()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, login)
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	out := map[string]string{"error": apperror.Message(err)}
+	if code := apperror.CodeOf(err); code != apperror.Unknown {
+		out["code"] = string(code)
+	}
+	writeJSON(w, status, out)
+}
@@ -0,0 +1,75 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"tarkov-account-switcher/internal/config"
+)
+
+// TestMain keeps the token file away from the real data directory
+func TestMain(m *testing.M) {
+	dir, err := os.MkdirTemp("", "tarkov-switcher-test-")
+	if err != nil {
+		panic(err)
+	}
+	os.Setenv(config.DataDirEnv, dir)
+	if err := config.EnsureDataDir(); err != nil {
+		panic(err)
+	}
+
+	code := m.Run()
+	os.RemoveAll(dir)
+	os.Exit(code)
+}
+
+func TestHandlerRejects(t *testing.T) {
+	const port = 47823
+	old, err := RegenerateToken()
+	if err != nil {
+		t.Fatal(err)
+	}
+	tok, err := RegenerateToken()
+	if err != nil {
+		t.Fatal(err)
+	}
+	handler := newHandler(port)
+
+	tests := []struct {
+		name   string
+		host   string
+		target string
+		auth   string
+		want   int
+	}{
+		{"valid", "127.0.0.1:47823", "/v1/watcher", "Bearer " + tok, http.StatusOK},
+		{"localhost", "localhost:47823", "/v1/watcher", "Bearer " + tok, http.StatusOK},
+		{"foreign host", "evil.example:47823", "/v1/watcher", "Bearer " + tok, http.StatusForbidden},
+		{"other port", "127.0.0.1:80", "/v1/watcher", "Bearer " + tok, http.StatusForbidden},
+		{"no port", "localhost", "/v1/watcher", "Bearer " + tok, http.StatusForbidden},
+		{"no token", "127.0.0.1:47823", "/v1/watcher", "", http.StatusUnauthorized},
+		{"wrong token", "127.0.0.1:47823", "/v1/watcher", "Bearer nope", http.StatusUnauthorized},
+		{"regenerated token", "127.0.0.1:47823", "/v1/watcher", "Bearer " + old, http.StatusUnauthorized},
+		{"not a bearer", "127.0.0.1:47823", "/v1/watcher", tok, http.StatusUnauthorized},
+		{"query token outside events", "127.0.0.1:47823", "/v1/watcher?token=" + tok, "", http.StatusUnauthorized},
+		{"wrong query token", "127.0.0.1:47823", "/v1/events?token=nope", "", http.StatusUnauthorized},
+		{"foreign host before token", "evil.example:47823", "/v1/watcher", "", http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tt.target, nil)
+			req.Host = tt.host
+			if tt.auth != "" {
+				req.Header.Set("Authorization", tt.auth)
+			}
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			if rec.Code != tt.want {
+				t.Errorf("status = %d, want %d (body %s)", rec.Code, tt.want, rec.Body)
+			}
+		})
+	}
+}
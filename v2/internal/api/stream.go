@@ -0,0 +1,102 @@
+package api
+
+import (
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+
+	"tarkov-account-switcher/internal/events"
+)
+
+const (
+	writeWait  = 10 * time.Second
+	pingPeriod = 30 * time.Second
+	pongWait   = pingPeriod + writeWait
+)
+
+var (
+	streamsMu sync.Mutex
+	streams   = make(map[*websocket.Conn]struct{})
+)
+
+var upgrader = websocket.Upgrader{
+	// Overlays are often local files or other origins; the token and the
+	// host check already keep foreign pages out
+	CheckOrigin: func(r *http.Request) bool { return true },
+}
+
+// handleEvents streams events as JSON text messages until the client goes away
+func handleEvents(w http.ResponseWriter, r *http.Request) {
+	conn, err := upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		return // Upgrade already replied with an error
+	}
+	defer conn.Close()
+
+	streamsMu.Lock()
+	streams[conn] = struct{}{}
+	streamsMu.Unlock()
+	defer func() {
+		streamsMu.Lock()
+		delete(streams, conn)
+		streamsMu.Unlock()
+	}()
+
+	stream, unsubscribe := events.Subscribe()
+	defer unsubscribe()
+
+	// The stream is one-way; reading only notices pongs and the close
+	closed := make(chan struct{})
+	go func() {
+		defer close(closed)
+		conn.SetReadLimit(maxBodyBytes)
+		conn.SetReadDeadline(time.Now().Add(pongWait))
+		conn.SetPongHandler(func(string) error {
+			return conn.SetReadDeadline(time.Now().Add(pongWait))
+		})
+		for {
+			if _, _, err := conn.ReadMessage(); err != nil {
+				return
+			}
+		}
+	}()
+
+	ping := time.NewTicker(pingPeriod)
+	defer ping.Stop()
+
+	for {
+		select {
+		case event, ok := <-stream:
+			if !ok {
+				return
+			}
+			conn.SetWriteDeadline(time.Now().Add(writeWait))
+			if err := conn.WriteJSON(event); err != nil {
+				return
+			}
+		case <-ping.C:
+			conn.SetWriteDeadline(time.Now().Add(writeWait))
+			if err := conn.WriteMessage(websocket.PingMessage, nil); err != nil {
+				return
+			}
+		case <-closed:
+			return
+		}
+	}
+}
+
+// closeStreams disconnects every event stream client. Closing the
+// connection ends the read loop, which ends its handleEvents.
+func closeStreams(reason string) {
+	streamsMu.Lock()
+	defer streamsMu.Unlock()
+
+	for conn := range streams {
+		conn.WriteControl(websocket.CloseMessage,
+			websocket.FormatCloseMessage(websocket.ClosePolicyViolation, reason),
+			time.Now().Add(time.Second))
+		conn.Close()
+	}
+}
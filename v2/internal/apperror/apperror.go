@@ -0,0 +1,169 @@
+// Package apperror is the catalogue of errors that reach the user. Every
+// error has a stable code for the API, CLI and frontend and a translation
+// key with parameters, so the UI shows the message in its own language
+// instead of hard-coded English or OS error text.
+package apperror
+
+import (
+	"context"
+	"errors"
+
+	"tarkov-account-switcher/internal/i18n"
+)
+
+// Code identifies an error kind. Codes are part of the API and never change.
+type Code string
+
+const (
+	Unknown              Code = "unknown"
+	AccountNotFound      Code = "account_not_found"
+	AccountsUnreadable   Code = "accounts_unreadable"
+	AccountsUnwritable   Code = "accounts_unwritable"
+	UnknownGame          Code = "unknown_game"
+	GameRunning          Code = "game_running"
+	LauncherNotFound     Code = "launcher_not_found"
+	LauncherStartFailed  Code = "launcher_start_failed"
+	KillTimeout          Code = "kill_timeout"
+	SettingsUnreadable   Code = "settings_unreadable"
+	SettingsUnwritable   Code = "settings_unwritable"
+	SessionUndecryptable Code = "session_undecryptable"
+	NotLoggedIn          Code = "not_logged_in"
+	NoAccountForLogin    Code = "no_account_for_login"
+	HookVeto             Code = "hook_veto"
+	ProfileFailed        Code = "profile_failed"
+	Busy                 Code = "busy"
+	Cancelled            Code = "cancelled"
+	TimedOut             Code = "timed_out"
+	AccountAmbiguous     Code = "account_ambiguous"
+	NoAccountGiven       Code = "no_account_given"
+	ImportVersion        Code = "import_version"
+	ImportInvalid        Code = "import_invalid"
+	InvalidArguments     Code = "invalid_arguments"
+	InvalidLink          Code = "invalid_link"
+	InvalidCacheTarget   Code = "invalid_cache_target"
+	NoLaunchArgs         Code = "no_launch_args"
+	GameNotStarted       Code = "game_not_started"
+)
+
+// messageKeys maps codes to their translation keys
+var messageKeys = map[Code]string{
+	AccountNotFound:      i18n.ErrAccountNotFound,
+	AccountsUnreadable:   i18n.ErrAccountsUnreadable,
+	AccountsUnwritable:   i18n.ErrAccountsUnwritable,
+	UnknownGame:          i18n.ErrUnknownGame,
+	GameRunning:          i18n.ErrGameRunning,
+	LauncherNotFound:     i18n.ErrLauncherNotFound,
+	LauncherStartFailed:  i18n.ErrLauncherStartFailed,
+	KillTimeout:          i18n.ErrKillTimeout,
+	SettingsUnreadable:   i18n.ErrSettingsUnreadable,
+	SettingsUnwritable:   i18n.ErrSettingsUnwritable,
+	SessionUndecryptable: i18n.ErrSessionUndecryptable,
+	NotLoggedIn:          i18n.ErrNotLoggedIn,
+	NoAccountForLogin:    i18n.ErrNoAccountForLogin,
+	HookVeto:             i18n.ErrHookVeto,
+	ProfileFailed:        i18n.ErrProfileFailed,
+	Busy:                 i18n.ErrBusy,
+	Cancelled:            i18n.ErrCancelled,
+	TimedOut:             i18n.ErrTimedOut,
+	AccountAmbiguous:     i18n.ErrAccountAmbiguous,
+	NoAccountGiven:       i18n.ErrNoAccountGiven,
+	ImportVersion:        i18n.ErrImportVersion,
+	ImportInvalid:        i18n.ErrImportInvalid,
+	InvalidArguments:     i18n.ErrInvalidArguments,
+	InvalidLink:          i18n.ErrInvalidLink,
+	InvalidCacheTarget:   i18n.ErrInvalidCacheTarget,
+	NoLaunchArgs:         i18n.ErrNoLaunchArgs,
+	GameNotStarted:       i18n.ErrGameNotStarted,
+}
+
+// Error is an error from the catalogue
+type Error struct {
+	Code   Code
+	Params map[string]string // placeholders of the message
+	Err    error             // underlying cause, may be nil
+}
+
+// New creates an error. params are placeholder name/value pairs; the cause,
+// if any, fills the {error} placeholder.
+func New(code Code, cause error, params ...string) *Error {
+	e := &Error{Code: code, Params: make(map[string]string, len(params)/2+1), Err: cause}
+	for i := 0; i+1 < len(params); i += 2 {
+		e.Params[params[i]] = params[i+1]
+	}
+	if cause != nil {
+		if _, ok := e.Params["error"]; !ok {
+			e.Params["error"] = cause.Error()
+		}
+	}
+	return e
+}
+
+// Wrap gives err the code unless it already carries one. nil stays nil.
+func Wrap(code Code, err error) error {
+	if err == nil {
+		return nil
+	}
+	var e *Error
+	if errors.As(err, &e) {
+		return err
+	}
+	return New(code, err)
+}
+
+// FromContext turns a context error into Cancelled or TimedOut
+func FromContext(err error) error {
+	if errors.Is(err, context.DeadlineExceeded) {
+		return New(TimedOut, err)
+	}
+	return New(Cancelled, err)
+}
+
+// Error returns the English message, for logs, hooks and the CLI
+func (e *Error) Error() string {
+	return i18n.TFLang("en", messageKeys[e.Code], e.Params)
+}
+
+// Unwrap returns the cause
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Message returns the message in the current UI language
+func (e *Error) Message() string {
+	return i18n.TF(messageKeys[e.Code], e.Params)
+}
+
+// CodeOf returns the code of err, Unknown for errors outside the catalogue
+// and "" for nil
+func CodeOf(err error) Code {
+	if err == nil {
+		return ""
+	}
+	var e *Error
+	if errors.As(err, &e) {
+		return e.Code
+	}
+	return Unknown
+}
+
+// Message returns the localized message of err. Errors outside the catalogue
+// keep their own text.
+func Message(err error) string {
+	if err == nil {
+		return ""
+	}
+	var e *Error
+	if errors.As(err, &e) {
+		return e.Message()
+	}
+	return err.Error()
+}
+
+// Localize returns err with its message in the current UI language, for
+// bindings whose errors are shown as they are
+func Localize(err error) error {
+	if err == nil {
+		return nil
+	}
+	return errors.New(Message(err))
+}
@@ -0,0 +1,62 @@
+package apperror
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestFromContext(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want Code
+	}{
+		{"cancelled", context.Canceled, Cancelled},
+		{"deadline", context.DeadlineExceeded, TimedOut},
+		{"wrapped deadline", fmt.Errorf("step: %w", context.DeadlineExceeded), TimedOut},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := FromContext(tt.err)
+			if got := CodeOf(err); got != tt.want {
+				t.Errorf("CodeOf(FromContext(%v)) = %q, want %q", tt.err, got, tt.want)
+			}
+			if !errors.Is(err, tt.err) {
+				t.Errorf("FromContext(%v) lost its cause", tt.err)
+			}
+		})
+	}
+}
+
+func TestCodeOf(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want Code
+	}{
+		{"nil", nil, ""},
+		{"outside the catalogue", errors.New("boom"), Unknown},
+		{"catalogue", New(Busy, nil), Busy},
+		{"wrapped", fmt.Errorf("switch: %w", New(GameRunning, nil)), GameRunning},
+		{"Wrap keeps the code", Wrap(Unknown, New(KillTimeout, nil)), KillTimeout},
+		{"Wrap adds a code", Wrap(SettingsUnwritable, errors.New("disk full")), SettingsUnwritable},
+		{"Wrap nil", Wrap(SettingsUnwritable, nil), ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := CodeOf(tt.err); got != tt.want {
+				t.Errorf("CodeOf(%v) = %q, want %q", tt.err, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEveryCodeHasAMessage(t *testing.T) {
+	for code, key := range messageKeys {
+		if msg := New(code, nil).Error(); msg == "" || msg == key {
+			t.Errorf("%s has no English message (key %s)", code, key)
+		}
+	}
+}
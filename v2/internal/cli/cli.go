@@ -0,0 +1,472 @@
+// Package cli runs the switcher headless when the binary is started with a
+// subcommand. It works on the accounts, launcher and config packages directly
+// and prints JSON to stdout.
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"os/signal"
+	"strings"
+	"time"
+
+	"tarkov-account-switcher/internal/accounts"
+	"tarkov-account-switcher/internal/apperror"
+	"tarkov-account-switcher/internal/config"
+	"tarkov-account-switcher/internal/doctor"
+	"tarkov-account-switcher/internal/hooks"
+)
+
+// Exit codes
+const (
+	exitOK           = 0
+	exitFailure      = 1
+	exitUsage        = 2
+	exitNeedsConfirm = 3 // switch policy wants --confirm
+)
+
+// loginTimeout bounds waiting for a login; the session watcher gives up after 5 minutes
+const loginTimeout = 5*time.Minute + 30*time.Second
+
+type command struct {
+	usage string
+	run   func(args []string) int
+}
+
+var commands map[string]command
+
+func init() {
+	commands = map[string]command{
+		"list":            {"list", runList},
+		"switch":          {"switch <name|id> [--game eft|arena] [--confirm] [--no-wait]", runSwitch},
+		"add":             {"add <name> <email> [--no-wait]", runAdd},
+		"delete":          {"delete <name|id>", runDelete},
+		"capture-current": {"capture-current", runCaptureCurrent},
+		"export":          {"export [--with-sessions] [--out file]", runExport},
+		"import":          {"import <file|->", runImport},
+		"doctor":          {"doctor [--bundle file.zip]", runDoctor},
+		"help":            {"help", runHelp},
+	}
+}
+
+// IsCommand reports whether args start with a CLI subcommand
+func IsCommand(args []string) bool {
+	if len(args) == 0 {
+		return false
+	}
+	_, ok := commands[args[0]]
+	return ok
+}
+
+// Run executes the subcommand in args and returns the process exit code
+func Run(args []string) int {
+	attachConsole()
+	defer hooks.Wait()
+
+	if !IsCommand(args) {
+		return runHelp(nil)
+	}
+
+	slog.Info("cli command", "command", args[0])
+	code := commands[args[0]].run(args[1:])
+	slog.Info("cli command finished", "command", args[0], "exitCode", code)
+	return code
+}
+
+func runHelp(args []string) int {
+	fmt.Fprintln(os.Stderr, "Usage: TarkovAccountSwitcher [--data-dir dir] <command>")
+	fmt.Fprintln(os.Stderr)
+	for _, name := range []string{"list", "switch", "add", "delete", "capture-current", "export", "import", "doctor"} {
+		fmt.Fprintln(os.Stderr, "  "+commands[name].usage)
+	}
+	return exitUsage
+}
+
+// parseArgs parses flags anywhere between positional arguments, so both
+// "switch Main --game arena" and "switch --game arena Main" work
+func parseArgs(fs *flag.FlagSet, args []string, positional int) ([]string, bool) {
+	fs.SetOutput(os.Stderr)
+	var rest []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			return nil, false
+		}
+		if fs.NArg() == 0 {
+			break
+		}
+		rest = append(rest, fs.Arg(0))
+		args = fs.Args()[1:]
+	}
+	if len(rest) != positional {
+		fmt.Fprintln(os.Stderr, "Usage: "+commands[fs.Name()].usage)
+		return nil, false
+	}
+	return rest, true
+}
+
+// writeJSON prints v as indented JSON to stdout
+func writeJSON(v interface{}) {
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	enc.Encode(v)
+}
+
+// fail prints an error and its code as JSON and returns exitFailure
+func fail(err error) int {
+	out := map[string]string{"error": apperror.Message(err)}
+	if code := apperror.CodeOf(err); code != apperror.Unknown {
+		out["code"] = string(code)
+	}
+	writeJSON(out)
+	return exitFailure
+}
+
+// interruptible returns a context that Ctrl+C cancels, so an interrupted
+// switch is rolled back instead of leaving the launcher half switched. Call
+// stop once the operation returned to restore the default handling.
+func interruptible() (context.Context, context.CancelFunc) {
+	return signal.NotifyContext(context.Background(), os.Interrupt)
+}
+
+// accountJSON is an account as printed by the CLI (no session data)
+type accountJSON struct {
+	ID              string `json:"id"`
+	Name            string `json:"name"`
+	Email           string `json:"email"`
+	HasSession      bool   `json:"hasSession"`
+	SessionCaptured string `json:"sessionCaptured,omitempty"`
+	DefaultGame     string `json:"defaultGame,omitempty"`
+	RecentSwitches  int    `json:"recentSwitches"`
+	CooldownSeconds int    `json:"cooldownSeconds"`
+}
+
+func toAccountJSON(acc *accounts.Account) accountJSON {
+	cooldown := acc.Cooldown(time.Now())
+	out := accountJSON{
+		ID:              acc.ID,
+		Name:            acc.Name,
+		Email:           config.MaskEmail(acc.Email),
+		HasSession:      acc.HasSession(),
+		SessionCaptured: acc.SessionCaptured,
+		DefaultGame:     acc.DefaultGame,
+		RecentSwitches:  cooldown.Switches,
+	}
+	if cooldown.Exceeded() {
+		out.CooldownSeconds = int((cooldown.Remaining + time.Second - 1) / time.Second)
+	}
+	return out
+}
+
+func runList(args []string) int {
+	fs := flag.NewFlagSet("list", flag.ContinueOnError)
+	if _, ok := parseArgs(fs, args, 0); !ok {
+		return exitUsage
+	}
+
+	accs, err := accounts.GetAccounts()
+	if err != nil {
+		return fail(err)
+	}
+	out := make([]accountJSON, len(accs))
+	for i := range accs {
+		out[i] = toAccountJSON(&accs[i])
+	}
+	writeJSON(out)
+	return exitOK
+}
+
+// switchJSON is the CLI result of a switch
+type switchJSON struct {
+	Success           bool        `json:"success"`
+	Account           accountJSON `json:"account"`
+	HasSession        bool        `json:"hasSession"`
+	Message           string      `json:"message,omitempty"`
+	Code              string      `json:"code,omitempty"`
+	Error             string      `json:"error,omitempty"`
+	Warnings          []string    `json:"warnings,omitempty"`
+	NeedsConfirmation bool        `json:"needsConfirmation,omitempty"`
+	LoggedIn          *bool       `json:"loggedIn,omitempty"` // set when the login was waited for
+}
+
+func runSwitch(args []string) int {
+	fs := flag.NewFlagSet("switch", flag.ContinueOnError)
+	game := fs.String("game", "", "game to select in the launcher (eft or arena)")
+	confirm := fs.Bool("confirm", false, "switch even if the switch policy asks for confirmation")
+	noWait := fs.Bool("no-wait", false, "return without waiting for the login")
+	rest, ok := parseArgs(fs, args, 1)
+	if !ok {
+		return exitUsage
+	}
+
+	account, err := accounts.FindAccount(rest[0])
+	if err != nil {
+		return fail(err)
+	}
+
+	// Register before switching so a fast verification is not missed
+	loggedIn := waitForLogin(account.ID)
+
+	ctx, stop := interruptible()
+	var result *accounts.SwitchResult
+	if *confirm {
+		result = accounts.ConfirmSwitchAccountGame(ctx, account.ID, *game)
+	} else {
+		result = accounts.SwitchAccountGame(ctx, account.ID, *game)
+	}
+	stop()
+
+	out := switchJSON{
+		Success:           result.Success,
+		Account:           toAccountJSON(account),
+		HasSession:        result.HasSession,
+		Message:           result.Message,
+		Code:              string(result.Code),
+		Error:             result.Error,
+		Warnings:          result.Warnings,
+		NeedsConfirmation: result.NeedsConfirmation,
+	}
+
+	switch {
+	case result.NeedsConfirmation:
+		writeJSON(out)
+		return exitNeedsConfirm
+	case !result.Success:
+		writeJSON(out)
+		return exitFailure
+	}
+
+	// Without verification a restored session counts as logged in right away
+	if *noWait || (result.HasSession && config.GetSettings().VerifySeconds <= 0) {
+		writeJSON(out)
+		return exitOK
+	}
+
+	ok = <-loggedIn
+	out.LoggedIn = &ok
+	writeJSON(out)
+	if !ok {
+		return exitFailure
+	}
+	return exitOK
+}
+
+// waitForLogin reports once the account is logged in: either the restored
+// session was verified or a new session was captured. It yields false if the
+// session was rejected and no new login followed in time.
+func waitForLogin(accountID string) <-chan bool {
+	done := make(chan bool, 1)
+	report := func(ok bool) {
+		select {
+		case done <- ok:
+		default:
+		}
+	}
+
+	accounts.SessionVerifiedCallback = func(result accounts.VerifyResult) {
+		// A rejected session starts the watcher; wait for its capture instead
+		if result.AccountID == accountID && result.Accepted {
+			report(true)
+		}
+	}
+	accounts.SessionCapturedCallback = func(id string) {
+		if id == accountID {
+			report(true)
+		}
+	}
+	time.AfterFunc(loginTimeout, func() { report(false) })
+
+	return done
+}
+
+func runAdd(args []string) int {
+	fs := flag.NewFlagSet("add", flag.ContinueOnError)
+	noWait := fs.Bool("no-wait", false, "return without waiting for the login")
+	rest, ok := parseArgs(fs, args, 2)
+	if !ok {
+		return exitUsage
+	}
+
+	name, email := strings.TrimSpace(rest[0]), strings.TrimSpace(rest[1])
+	if name == "" {
+		return fail(apperror.New(apperror.InvalidArguments, nil, "arg", "name"))
+	}
+	if !strings.Contains(email, "@") {
+		return fail(apperror.New(apperror.InvalidArguments, nil, "arg", "email"))
+	}
+
+	captured := make(chan string, 4)
+	accounts.SessionCapturedCallback = func(accountID string) {
+		select {
+		case captured <- accountID:
+		default:
+		}
+	}
+
+	ctx, stop := interruptible()
+	newID, err := accounts.AddAccount(ctx, name, email)
+	stop()
+	if err != nil {
+		return fail(err)
+	}
+
+	account, err := accounts.GetAccountByID(newID)
+	if err != nil || account == nil {
+		return fail(apperror.New(apperror.AccountsUnwritable, err))
+	}
+
+	out := struct {
+		Account  accountJSON `json:"account"`
+		LoggedIn *bool       `json:"loggedIn,omitempty"`
+	}{Account: toAccountJSON(account)}
+
+	if *noWait {
+		writeJSON(out)
+		return exitOK
+	}
+
+	ok = false
+	timeout := time.After(loginTimeout)
+wait:
+	for {
+		select {
+		case id := <-captured:
+			if id == newID {
+				ok = true
+				break wait
+			}
+		case <-timeout:
+			break wait
+		}
+	}
+	out.LoggedIn = &ok
+	if account, err := accounts.GetAccountByID(newID); err == nil && account != nil {
+		out.Account = toAccountJSON(account)
+	}
+	writeJSON(out)
+	if !ok {
+		return exitFailure
+	}
+	return exitOK
+}
+
+func runDelete(args []string) int {
+	fs := flag.NewFlagSet("delete", flag.ContinueOnError)
+	rest, ok := parseArgs(fs, args, 1)
+	if !ok {
+		return exitUsage
+	}
+
+	account, err := accounts.FindAccount(rest[0])
+	if err != nil {
+		return fail(err)
+	}
+	ctx, stop := interruptible()
+	defer stop()
+	if err := accounts.DeleteAccount(ctx, account.ID); err != nil {
+		return fail(err)
+	}
+	writeJSON(map[string]accountJSON{"deleted": toAccountJSON(account)})
+	return exitOK
+}
+
+func runCaptureCurrent(args []string) int {
+	fs := flag.NewFlagSet("capture-current", flag.ContinueOnError)
+	if _, ok := parseArgs(fs, args, 0); !ok {
+		return exitUsage
+	}
+
+	account, err := accounts.CaptureCurrentSession()
+	if err != nil {
+		return fail(err)
+	}
+	writeJSON(map[string]accountJSON{"captured": toAccountJSON(account)})
+	return exitOK
+}
+
+func runExport(args []string) int {
+	fs := flag.NewFlagSet("export", flag.ContinueOnError)
+	withSessions := fs.Bool("with-sessions", false, "include login sessions in plaintext")
+	outFile := fs.String("out", "", "write to file instead of stdout")
+	if _, ok := parseArgs(fs, args, 0); !ok {
+		return exitUsage
+	}
+
+	export, err := accounts.ExportAccounts(*withSessions)
+	if err != nil {
+		return fail(err)
+	}
+	if *outFile == "" {
+		writeJSON(export)
+		return exitOK
+	}
+
+	data, err := json.MarshalIndent(export, "", "  ")
+	if err != nil {
+		return fail(err)
+	}
+	// Sessions are plaintext, so keep the file private
+	if err := os.WriteFile(*outFile, data, 0600); err != nil {
+		return fail(err)
+	}
+	writeJSON(map[string]interface{}{"file": *outFile, "accounts": len(export.Accounts)})
+	return exitOK
+}
+
+func runDoctor(args []string) int {
+	fs := flag.NewFlagSet("doctor", flag.ContinueOnError)
+	bundle := fs.String("bundle", "", "also write a redacted support bundle zip")
+	if _, ok := parseArgs(fs, args, 0); !ok {
+		return exitUsage
+	}
+
+	report := doctor.Run()
+	if *bundle != "" {
+		if err := doctor.WriteBundle(*bundle, report); err != nil {
+			return fail(err)
+		}
+		writeJSON(map[string]interface{}{"report": report, "bundle": *bundle})
+	} else {
+		writeJSON(report)
+	}
+
+	if report.Failed() {
+		return exitFailure
+	}
+	return exitOK
+}
+
+func runImport(args []string) int {
+	fs := flag.NewFlagSet("import", flag.ContinueOnError)
+	rest, ok := parseArgs(fs, args, 1)
+	if !ok {
+		return exitUsage
+	}
+
+	var data []byte
+	var err error
+	if rest[0] == "-" {
+		data, err = io.ReadAll(os.Stdin)
+	} else {
+		data, err = os.ReadFile(rest[0])
+	}
+	if err != nil {
+		return fail(err)
+	}
+
+	var export accounts.Export
+	if err := json.Unmarshal(data, &export); err != nil {
+		return fail(err)
+	}
+
+	result, err := accounts.ImportAccounts(&export)
+	if err != nil {
+		return fail(err)
+	}
+	writeJSON(result)
+	return exitOK
+}
@@ -0,0 +1,6 @@
+//go:build !windows
+
+package cli
+
+// attachConsole is a no-op outside Windows, where stdout is always connected
+func attachConsole() {}
@@ -0,0 +1,39 @@
+package cli
+
+import (
+	"os"
+	"syscall"
+)
+
+var (
+	kernel32dll       = syscall.NewLazyDLL("kernel32.dll")
+	procAttachConsole = kernel32dll.NewProc("AttachConsole")
+)
+
+const attachParentProcess = ^uintptr(0) // ATTACH_PARENT_PROCESS ((DWORD)-1)
+
+// attachConsole connects the GUI-subsystem binary to the console it was
+// started from, so CLI output shows up there. Redirected handles (pipes,
+// files) are kept as they are.
+func attachConsole() {
+	stdout, _ := syscall.GetStdHandle(syscall.STD_OUTPUT_HANDLE)
+	stderr, _ := syscall.GetStdHandle(syscall.STD_ERROR_HANDLE)
+	if stdout != 0 && stdout != syscall.InvalidHandle && stderr != 0 && stderr != syscall.InvalidHandle {
+		return
+	}
+
+	if r, _, _ := procAttachConsole.Call(attachParentProcess); r == 0 {
+		return // started without a console (e.g. from Explorer)
+	}
+
+	conout, err := os.OpenFile("CONOUT$", os.O_WRONLY, 0)
+	if err != nil {
+		return
+	}
+	if stdout == 0 || stdout == syscall.InvalidHandle {
+		os.Stdout = conout
+	}
+	if stderr == 0 || stderr == syscall.InvalidHandle {
+		os.Stderr = conout
+	}
+}
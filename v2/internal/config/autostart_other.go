@@ -0,0 +1,58 @@
+//go:build !windows
+
+package config
+
+import (
+	"os"
+	"path/filepath"
+)
+
+// autostartFile returns the XDG autostart entry for the app
+func autostartFile() (string, error) {
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(configDir, "autostart", "tarkov-account-switcher.desktop"), nil
+}
+
+// ApplyAutoStart creates or removes the XDG autostart entry
+func ApplyAutoStart(enabled bool) error {
+	path, err := autostartFile()
+	if err != nil {
+		return err
+	}
+
+	if !enabled {
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		return nil
+	}
+
+	exePath, err := os.Executable()
+	if err != nil {
+		return err
+	}
+
+	entry := "[Desktop Entry]\n" +
+		"Type=Application\n" +
+		"Name=Tarkov Account Switcher\n" +
+		"Exec=\"" + exePath + "\"\n" +
+		"X-GNOME-Autostart-enabled=true\n"
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, []byte(entry), 0644)
+}
+
+// IsAutoStartEnabled checks if the XDG autostart entry exists
+func IsAutoStartEnabled() bool {
+	path, err := autostartFile()
+	if err != nil {
+		return false
+	}
+	_, err = os.Stat(path)
+	return err == nil
+}
@@ -0,0 +1,122 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+// Data directory sources, in priority order
+const (
+	SourceFlag     = "--data-dir"
+	SourceEnv      = DataDirEnv
+	SourcePortable = "portable"
+	SourceXDG      = "xdg"
+	SourceAppData  = "appdata"
+)
+
+// DataDirEnv overrides the data directory like --data-dir
+const DataDirEnv = "TARKOV_SWITCHER_DATA_DIR"
+
+// PortableMarker is the file next to the executable that enables portable mode
+const PortableMarker = "portable.txt"
+
+const appDirName = "TarkovAccountSwitcher"
+
+var dataDirFlag string
+
+// SetDataDirFlag sets the --data-dir value. Must be called before the first GetPaths.
+func SetDataDirFlag(dir string) {
+	dataDirFlag = dir
+}
+
+// ParseDataDirFlag extracts --data-dir (as "--data-dir X" or "--data-dir=X")
+// from args and returns the remaining arguments
+func ParseDataDirFlag(args []string) (string, []string) {
+	var dir string
+	rest := make([]string, 0, len(args))
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		switch {
+		case arg == "--data-dir" && i+1 < len(args):
+			dir = args[i+1]
+			i++
+		case strings.HasPrefix(arg, "--data-dir="):
+			dir = strings.TrimPrefix(arg, "--data-dir=")
+		default:
+			rest = append(rest, arg)
+		}
+	}
+	return dir, rest
+}
+
+// dataDirs is the outcome of data directory resolution
+type dataDirs struct {
+	dataDir   string // accounts, key, vault, profiles, temp
+	configDir string // settings.json
+	source    string
+}
+
+// resolveDataDirs picks the data directory from the first source that applies:
+// --data-dir, the environment override, portable mode, XDG base dirs on Linux,
+// and %APPDATA% on Windows. Relative results are rejected instead of silently
+// writing into the working directory.
+func resolveDataDirs() (dataDirs, error) {
+	if dataDirFlag != "" {
+		return absDataDirs(dataDirFlag, SourceFlag)
+	}
+
+	if dir := os.Getenv(DataDirEnv); dir != "" {
+		return absDataDirs(dir, SourceEnv)
+	}
+
+	if exe, err := os.Executable(); err == nil {
+		exeDir := filepath.Dir(exe)
+		if _, err := os.Stat(filepath.Join(exeDir, PortableMarker)); err == nil {
+			return absDataDirs(filepath.Join(exeDir, "data"), SourcePortable)
+		}
+	}
+
+	if runtime.GOOS != "windows" {
+		home, _ := os.UserHomeDir()
+		dataHome := xdgDir("XDG_DATA_HOME", home, ".local", "share")
+		configHome := xdgDir("XDG_CONFIG_HOME", home, ".config")
+		if dataHome == "" || configHome == "" {
+			return dataDirs{}, errors.New("cannot determine XDG data directory")
+		}
+		return dataDirs{
+			dataDir:   filepath.Join(dataHome, appDirName),
+			configDir: filepath.Join(configHome, appDirName),
+			source:    SourceXDG,
+		}, nil
+	}
+
+	appData := os.Getenv("APPDATA")
+	if appData == "" || !filepath.IsAbs(appData) {
+		return dataDirs{}, errors.New("APPDATA is not set")
+	}
+	return absDataDirs(filepath.Join(appData, appDirName), SourceAppData)
+}
+
+// absDataDirs uses dir for both data and settings
+func absDataDirs(dir, source string) (dataDirs, error) {
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return dataDirs{}, err
+	}
+	return dataDirs{dataDir: abs, configDir: abs, source: source}, nil
+}
+
+// xdgDir returns $env if it is an absolute path, else home joined with fallback.
+// Per the XDG spec relative values are ignored.
+func xdgDir(env, home string, fallback ...string) string {
+	if dir := os.Getenv(env); filepath.IsAbs(dir) {
+		return dir
+	}
+	if home == "" {
+		return ""
+	}
+	return filepath.Join(append([]string{home}, fallback...)...)
+}
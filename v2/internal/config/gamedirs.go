@@ -0,0 +1,104 @@
+package config
+
+import (
+	"os"
+	"os/user"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+// WineSettings configures running the BSG launcher under Wine/Proton on Linux
+type WineSettings struct {
+	Prefix  string            `json:"prefix"`  // WINEPREFIX (default $WINEPREFIX or ~/.wine)
+	Command []string          `json:"command"` // e.g. ["wine"] or ["umu-run"]; the launcher path is appended
+	User    string            `json:"user"`    // folder in drive_c/users (default $USER, "steamuser" under Proton)
+	Env     map[string]string `json:"env"`     // extra environment, e.g. GAMEID for umu
+}
+
+// GameDirs holds the Windows user folders the BSG launcher and EFT use,
+// resolved to paths on this machine
+type GameDirs struct {
+	RoamingAppData string // %APPDATA%
+	LocalAppData   string // %LOCALAPPDATA%
+	Temp           string // %TEMP%
+}
+
+// IsWine reports whether the launcher runs under Wine instead of natively
+func IsWine() bool {
+	return runtime.GOOS != "windows"
+}
+
+// GetGameDirs resolves the launcher's user folders - from the environment on
+// Windows, inside the configured Wine prefix everywhere else
+func GetGameDirs() GameDirs {
+	if !IsWine() {
+		return GameDirs{
+			RoamingAppData: os.Getenv("APPDATA"),
+			LocalAppData:   os.Getenv("LOCALAPPDATA"),
+			Temp:           os.TempDir(),
+		}
+	}
+
+	home := filepath.Join(WinePrefix(), "drive_c", "users", wineUser())
+	return GameDirs{
+		RoamingAppData: filepath.Join(home, "AppData", "Roaming"),
+		LocalAppData:   filepath.Join(home, "AppData", "Local"),
+		Temp:           filepath.Join(home, "AppData", "Local", "Temp"),
+	}
+}
+
+// LauncherSettingsPath returns the BSG launcher settings file
+func LauncherSettingsPath() string {
+	return filepath.Join(GetGameDirs().RoamingAppData, "Battlestate Games", "BsgLauncher", "settings")
+}
+
+// WinePrefix returns the configured Wine prefix
+func WinePrefix() string {
+	if prefix := GetSettings().Wine.Prefix; prefix != "" {
+		return prefix
+	}
+	if prefix := os.Getenv("WINEPREFIX"); prefix != "" {
+		return prefix
+	}
+	home, _ := os.UserHomeDir()
+	return filepath.Join(home, ".wine")
+}
+
+// wineUser returns the user folder name inside the prefix
+func wineUser() string {
+	if name := GetSettings().Wine.User; name != "" {
+		return name
+	}
+	if name := os.Getenv("USER"); name != "" {
+		return name
+	}
+	if u, err := user.Current(); err == nil {
+		return u.Username
+	}
+	return "steamuser"
+}
+
+// HostPath converts a Windows path such as the launcher path to a path on
+// this machine. Under Wine drive letters map to the prefix's dosdevices.
+func HostPath(winPath string) string {
+	if !IsWine() {
+		return winPath
+	}
+
+	p := strings.ReplaceAll(winPath, `\`, "/")
+	if len(p) >= 2 && p[1] == ':' {
+		drive := strings.ToLower(p[:2])
+		return filepath.Join(WinePrefix(), "dosdevices", drive, filepath.FromSlash(p[2:]))
+	}
+	return filepath.FromSlash(p)
+}
+
+// LauncherPathFor converts a path on this machine to one the launcher can use.
+// Under Wine the host filesystem is reachable through drive Z:.
+func LauncherPathFor(hostPath string) string {
+	if !IsWine() {
+		return hostPath
+	}
+	return "Z:" + strings.ReplaceAll(hostPath, "/", `\`)
+}
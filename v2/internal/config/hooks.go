@@ -0,0 +1,50 @@
+package config
+
+import "errors"
+
+// Hook events, in the order they occur during a switch
+const (
+	HookBeforeSwitch    = "beforeSwitch"    // before anything is touched, may veto
+	HookLauncherStarted = "launcherStarted" // launcher was started for the new account
+	HookLoggedIn        = "loggedIn"        // login was verified or a re-login captured
+	HookSessionCaptured = "sessionCaptured" // watcher saved a new session
+	HookSwitchFailed    = "switchFailed"    // switch was rolled back
+)
+
+// DefaultHookTimeout is used when a hook sets no timeout
+const DefaultHookTimeout = 30
+
+// Hook is a user command run on a switch event. The command is executed
+// directly (no shell); use e.g. ["cmd", "/C", ...] or ["sh", "-c", ...] for one.
+type Hook struct {
+	Event   string   `json:"event"`
+	Command []string `json:"command"`
+	Timeout int      `json:"timeoutSeconds,omitempty"` // seconds, default DefaultHookTimeout
+
+	// Veto makes a failing beforeSwitch hook cancel the switch
+	Veto bool `json:"veto,omitempty"`
+}
+
+// IsValidHookEvent reports whether event is a known hook event
+func IsValidHookEvent(event string) bool {
+	switch event {
+	case HookBeforeSwitch, HookLauncherStarted, HookLoggedIn, HookSessionCaptured, HookSwitchFailed:
+		return true
+	}
+	return false
+}
+
+// SetHooks validates and saves the hook list
+func SetHooks(hooks []Hook) error {
+	for _, h := range hooks {
+		if !IsValidHookEvent(h.Event) {
+			return errors.New("unknown hook event: " + h.Event)
+		}
+		if len(h.Command) == 0 || h.Command[0] == "" {
+			return errors.New("hook for " + h.Event + " has no command")
+		}
+	}
+	settings := GetSettings()
+	settings.Hooks = hooks
+	return SaveSettings(settings)
+}
@@ -0,0 +1,33 @@
+package config
+
+// NotificationSettings selects which desktop notifications are shown.
+// Results of shortcut, link and API actions are always shown.
+type NotificationSettings struct {
+	SessionCaptured bool `json:"sessionCaptured"`
+	CaptureTimeout  bool `json:"captureTimeout"`
+	SwitchFailed    bool `json:"switchFailed"`
+	SessionExpiring bool `json:"sessionExpiring"`
+
+	// ExpiryDays is the age after which a session that was not refreshed
+	// by a switch or capture counts as expiring
+	ExpiryDays int `json:"expiryDays"`
+}
+
+// DefaultNotifications enables every category
+var DefaultNotifications = NotificationSettings{
+	SessionCaptured: true,
+	CaptureTimeout:  true,
+	SwitchFailed:    true,
+	SessionExpiring: true,
+	ExpiryDays:      25,
+}
+
+// SetNotificationSettings sets and saves the notification settings
+func SetNotificationSettings(notifications NotificationSettings) error {
+	if notifications.ExpiryDays <= 0 {
+		notifications.ExpiryDays = DefaultNotifications.ExpiryDays
+	}
+	settings := GetSettings()
+	settings.Notifications = notifications
+	return SaveSettings(settings)
+}
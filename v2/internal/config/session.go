@@ -0,0 +1,103 @@
+package config
+
+// Session field targets
+const (
+	TargetLauncher = "launcher" // BsgLauncher settings file
+	TargetGameIni  = "gameIni"  // EFT Settings/Game.ini
+)
+
+// SessionField describes one value that is captured per account and written
+// back on switch. Together the fields form the session schema that drives
+// both capture and restore.
+type SessionField struct {
+	Key      string      `json:"key"`                // key in the saved session
+	Target   string      `json:"target,omitempty"`   // where the value lives (default launcher)
+	Field    string      `json:"field,omitempty"`    // name in the target file (default Key)
+	Fixed    interface{} `json:"fixed,omitempty"`    // captured as this value instead of being read
+	Disabled bool        `json:"disabled,omitempty"` // removes a default field via user override
+}
+
+// TargetField returns the name of the field in the target file
+func (f SessionField) TargetField() string {
+	if f.Field != "" {
+		return f.Field
+	}
+	return f.Key
+}
+
+// TargetOrDefault returns the field's target, defaulting to the launcher settings
+func (f SessionField) TargetOrDefault() string {
+	if f.Target != "" {
+		return f.Target
+	}
+	return TargetLauncher
+}
+
+// Keys of the session fields the switcher reads itself to tell who is
+// logged in to the launcher
+const (
+	SessionLogin        = "login"
+	SessionAccessToken  = "at"
+	SessionRefreshToken = "rt"
+	SessionTokenExpiry  = "atet"
+)
+
+// DefaultSessionFields is the built-in session schema.
+// keepLoggedIn and saveLogin are always captured as true - BSG expires
+// sessions early when they are false.
+var DefaultSessionFields = []SessionField{
+	{Key: SessionLogin},
+	{Key: SessionAccessToken},
+	{Key: SessionRefreshToken},
+	{Key: SessionTokenExpiry},
+	{Key: "sysInfCheck"},
+	{Key: "keepLoggedIn", Fixed: true},
+	{Key: "saveLogin", Fixed: true},
+	{Key: "selectedGame"},
+	{Key: "environmentUiType", Target: TargetGameIni, Field: "EnvironmentUiType"},
+}
+
+// SessionSchema returns the default session fields merged with the user
+// overrides from settings. Overrides replace defaults with the same key,
+// new keys are appended, and disabled fields are dropped.
+func SessionSchema() []SessionField {
+	overrides := GetSettings().SessionFields
+
+	merged := make([]SessionField, 0, len(DefaultSessionFields)+len(overrides))
+	index := make(map[string]int)
+	for _, f := range DefaultSessionFields {
+		index[f.Key] = len(merged)
+		merged = append(merged, f)
+	}
+	for _, f := range overrides {
+		if f.Key == "" {
+			continue
+		}
+		if i, ok := index[f.Key]; ok {
+			merged[i] = f
+			continue
+		}
+		index[f.Key] = len(merged)
+		merged = append(merged, f)
+	}
+
+	schema := merged[:0]
+	for _, f := range merged {
+		if !f.Disabled {
+			schema = append(schema, f)
+		}
+	}
+	return schema
+}
+
+// LauncherFieldName returns the name in the launcher settings of the session
+// field with the given key. Keys the schema does not map to the launcher
+// settings are used as they are.
+func LauncherFieldName(key string) string {
+	for _, f := range SessionSchema() {
+		if f.Key == key && f.TargetOrDefault() == TargetLauncher {
+			return f.TargetField()
+		}
+	}
+	return key
+}
@@ -2,6 +2,7 @@ package config
 
 import (
 	"encoding/json"
+	"errors"
 	"os"
 	"path/filepath"
 	"sync"
@@ -14,20 +15,106 @@ type Settings struct {
 	StreamerMode bool   `json:"streamerMode"`
 	Theme        string `json:"theme"`
 	AutoStart    bool   `json:"autoStart"`
+	URIScheme    bool   `json:"uriScheme"` // tarkovswitch:// links are registered
+
+	// VerifySeconds is how long the launcher settings are watched after a
+	// switch to confirm the restored session was accepted. 0 disables it.
+	VerifySeconds int `json:"verifySeconds"`
+
+	// CacheTargets lists the directories cleared on every switch.
+	// Entries may use %TEMP%, %LOCALAPPDATA% and %LAUNCHER_DIR%.
+	CacheTargets []string `json:"cacheTargets"`
+
+	// LaunchGameAfterLogin starts the account's game once the login is verified.
+	// GameLaunchArgs holds the launcher arguments used to start each game.
+	LaunchGameAfterLogin bool                `json:"launchGameAfterLogin"`
+	GameLaunchArgs       map[string][]string `json:"gameLaunchArgs"`
+
+	// ProfileInclude/ProfileExclude select which files of the EFT Settings
+	// directory belong to per-account game profiles. Everything else stays global.
+	ProfileInclude []string `json:"profileInclude"`
+	ProfileExclude []string `json:"profileExclude"`
+
+	// CefProfileMaxBytes caps the launcher cookie/local storage snapshot per account
+	CefProfileMaxBytes int64 `json:"cefProfileMaxBytes"`
+
+	// SessionFields overrides or extends the built-in session schema (see SessionSchema)
+	SessionFields []SessionField `json:"sessionFields,omitempty"`
+
+	// SessionFilePatterns are the file names in the launcher data directory
+	// that may hold auth state and are removed when a fresh login is forced
+	SessionFilePatterns []string `json:"sessionFilePatterns"`
+
+	// Wine configures the launcher backend on Linux (ignored on Windows)
+	Wine WineSettings `json:"wine"`
+
+	// Hooks are user commands run around a switch (see Hook)
+	Hooks []Hook `json:"hooks,omitempty"`
+
+	// SwitchPolicy warns about or blocks switching into an account too often
+	SwitchPolicy SwitchPolicy `json:"switchPolicy"`
+
+	// API configures the local HTTP API (off by default)
+	API APISettings `json:"api"`
+
+	// Notifications toggles desktop notifications per category
+	Notifications NotificationSettings `json:"notifications"`
+
+	// LogLevel is the lowest level written to the log: debug, info, warn or error
+	LogLevel string `json:"logLevel"`
+}
+
+// APISettings configures the loopback HTTP API
+type APISettings struct {
+	Enabled bool `json:"enabled"`
+	Port    int  `json:"port"`
+}
+
+// DefaultAPIPort is the port the HTTP API listens on unless configured
+const DefaultAPIPort = 47823
+
+// DefaultSessionFilePatterns are the launcher files known to hold auth state
+var DefaultSessionFilePatterns = []string{
+	"user.json",
+	"session",
+	"token",
+	".session",
+	"auth",
+	"auth.json",
+	"login",
+	"login.json",
+}
+
+// DefaultGameLaunchArgs are the launcher arguments for the play action of each game.
+// BSG does not document any, so there are none by default: launching the game
+// after login only works once gameLaunchArgs is set in settings.json.
+var DefaultGameLaunchArgs = map[string][]string{}
+
+// DefaultCacheTargets are the EFT/Arena and launcher caches cleared on switch
+var DefaultCacheTargets = []string{
+	`%TEMP%\Battlestate Games\EscapeFromTarkov`,
+	`%TEMP%\Battlestate Games\EscapeFromTarkovArena`,
+	`%LOCALAPPDATA%\Battlestate Games\BsgLauncher\CefCache\Cache`,
+	`%LAUNCHER_DIR%\Temp`,
 }
 
 // Paths holds all the important file paths for the application
 type Paths struct {
-	DataDir            string
-	AccountsFile       string
-	SettingsFile       string
-	KeyFile            string
-	TempFolder         string
-	LauncherSettingsPath string
+	DataDir       string
+	DataDirSource string // which source chose DataDir (see resolveDataDirs)
+	AccountsFile  string
+	SettingsFile  string
+	KeyFile       string
+	TempFolder    string
+	ProfilesDir   string
+	VaultDir      string
+	APITokenFile  string
+	LogDir        string
 }
 
 var (
 	appPaths       *Paths
+	pathsErr       error
 	pathsOnce      sync.Once
 	cachedSettings *Settings
 )
@@ -35,27 +122,41 @@ var (
 // GetPaths returns the application paths, initializing them exactly once
 func GetPaths() *Paths {
 	pathsOnce.Do(func() {
-		appData := os.Getenv("APPDATA")
-		dataDir := filepath.Join(appData, "TarkovAccountSwitcher")
+		dirs, err := resolveDataDirs()
+		if err != nil {
+			pathsErr = err
+		}
+		dataDir := dirs.dataDir
 
 		appPaths = &Paths{
-			DataDir:              dataDir,
-			AccountsFile:         filepath.Join(dataDir, "accounts.json"),
-			SettingsFile:         filepath.Join(dataDir, "settings.json"),
-			KeyFile:              filepath.Join(dataDir, ".key"),
-			TempFolder:           filepath.Join(dataDir, "temp"),
-			LauncherSettingsPath: filepath.Join(appData, "Battlestate Games", "BsgLauncher", "settings"),
+			DataDir:       dataDir,
+			DataDirSource: dirs.source,
+			AccountsFile:  filepath.Join(dataDir, "accounts.json"),
+			SettingsFile:  filepath.Join(dirs.configDir, "settings.json"),
+			KeyFile:       filepath.Join(dataDir, ".key"),
+			TempFolder:    filepath.Join(dataDir, "temp"),
+			ProfilesDir:   filepath.Join(dataDir, "profiles"),
+			VaultDir:      filepath.Join(dataDir, "vault"),
+			APITokenFile:  filepath.Join(dataDir, "api-token"),
+			LogDir:        filepath.Join(dataDir, "logs"),
 		}
 	})
 	return appPaths
 }
 
-// EnsureDataDir creates the data directory if it doesn't exist
+// EnsureDataDir creates the data directory if it doesn't exist.
+// Fails if no data directory could be resolved.
 func EnsureDataDir() error {
 	paths := GetPaths()
+	if pathsErr != nil {
+		return pathsErr
+	}
 	if err := os.MkdirAll(paths.DataDir, 0755); err != nil {
 		return err
 	}
+	if err := os.MkdirAll(filepath.Dir(paths.SettingsFile), 0755); err != nil {
+		return err
+	}
 	if err := os.MkdirAll(paths.TempFolder, 0755); err != nil {
 		return err
 	}
@@ -71,8 +172,29 @@ func GetSettings() *Settings {
 	paths := GetPaths()
 
 	settings := &Settings{
-		LauncherPath: `C:\Battlestate Games\BsgLauncher\BsgLauncher.exe`,
-		Language:     "",
+		LauncherPath:  `C:\Battlestate Games\BsgLauncher\BsgLauncher.exe`,
+		Language:      "",
+		VerifySeconds: 30,
+		CacheTargets:  append([]string(nil), DefaultCacheTargets...),
+
+		ProfileInclude: []string{"*"},
+		ProfileExclude: []string{},
+
+		CefProfileMaxBytes:  20 << 20,
+		SessionFilePatterns: append([]string(nil), DefaultSessionFilePatterns...),
+
+		Wine: WineSettings{
+			Command: []string{"wine"},
+		},
+
+		SwitchPolicy:  DefaultSwitchPolicy,
+		API:           APISettings{Port: DefaultAPIPort},
+		Notifications: DefaultNotifications,
+		LogLevel:      "info",
+	}
+	settings.GameLaunchArgs = make(map[string][]string, len(DefaultGameLaunchArgs))
+	for game, args := range DefaultGameLaunchArgs {
+		settings.GameLaunchArgs[game] = args
 	}
 
 	data, err := os.ReadFile(paths.SettingsFile)
@@ -138,6 +260,93 @@ func SetAutoStart(enabled bool) error {
 	return SaveSettings(settings)
 }
 
+// SetVerifySeconds sets and saves the post-switch verification window
+func SetVerifySeconds(seconds int) error {
+	if seconds < 0 {
+		seconds = 0
+	}
+	settings := GetSettings()
+	settings.VerifySeconds = seconds
+	return SaveSettings(settings)
+}
+
+// SetCacheTargets sets and saves the global cache target list.
+// A nil list restores the defaults, an empty list disables cache clearing.
+func SetCacheTargets(targets []string) error {
+	if targets == nil {
+		targets = append([]string(nil), DefaultCacheTargets...)
+	}
+	settings := GetSettings()
+	settings.CacheTargets = targets
+	return SaveSettings(settings)
+}
+
+// HasGameLaunchArgs reports whether gameLaunchArgs has launcher arguments for game
+func HasGameLaunchArgs(game string) bool {
+	return len(GetSettings().GameLaunchArgs[game]) > 0
+}
+
+// SetLaunchGameAfterLogin sets and saves the switch-and-play setting
+func SetLaunchGameAfterLogin(enabled bool) error {
+	settings := GetSettings()
+	settings.LaunchGameAfterLogin = enabled
+	return SaveSettings(settings)
+}
+
+// SetProfileFilter sets and saves the include/exclude patterns for game profiles
+func SetProfileFilter(include, exclude []string) error {
+	if include == nil {
+		include = []string{"*"}
+	}
+	if exclude == nil {
+		exclude = []string{}
+	}
+	settings := GetSettings()
+	settings.ProfileInclude = include
+	settings.ProfileExclude = exclude
+	return SaveSettings(settings)
+}
+
+// SetSessionFields sets and saves the session schema overrides
+func SetSessionFields(fields []SessionField) error {
+	settings := GetSettings()
+	settings.SessionFields = fields
+	return SaveSettings(settings)
+}
+
+// SetSessionFilePatterns sets and saves the session file patterns (nil restores the defaults)
+func SetSessionFilePatterns(patterns []string) error {
+	if patterns == nil {
+		patterns = append([]string(nil), DefaultSessionFilePatterns...)
+	}
+	settings := GetSettings()
+	settings.SessionFilePatterns = patterns
+	return SaveSettings(settings)
+}
+
+// SetWineSettings sets and saves the Wine launcher backend settings
+func SetWineSettings(wine WineSettings) error {
+	if len(wine.Command) == 0 {
+		wine.Command = []string{"wine"}
+	}
+	settings := GetSettings()
+	settings.Wine = wine
+	return SaveSettings(settings)
+}
+
+// SetAPISettings sets and saves the HTTP API settings
+func SetAPISettings(api APISettings) error {
+	if api.Port == 0 {
+		api.Port = DefaultAPIPort
+	}
+	if api.Port < 1024 || api.Port > 65535 {
+		return errors.New("API port must be between 1024 and 65535")
+	}
+	settings := GetSettings()
+	settings.API = api
+	return SaveSettings(settings)
+}
+
 // IsStreamerMode returns whether streamer mode is enabled
 func IsStreamerMode() bool {
 	return GetSettings().StreamerMode
@@ -148,7 +357,12 @@ func MaskEmail(email string) string {
 	if !IsStreamerMode() {
 		return email
 	}
+	return HideEmail(email)
+}
 
+// HideEmail masks an email regardless of streamer mode, for data that
+// leaves the app (hooks, logs)
+func HideEmail(email string) string {
 	// Find @ position
 	atIdx := -1
 	for i, c := range email {
@@ -0,0 +1,33 @@
+package config
+
+import "strings"
+
+// URIScheme is the custom link scheme handled by the app (tarkovswitch://switch/<id>)
+const URIScheme = "tarkovswitch"
+
+// shortcutFileName turns a shortcut title into a safe file name
+func shortcutFileName(title string) string {
+	name := strings.Map(func(r rune) rune {
+		switch r {
+		case '<', '>', ':', '"', '/', '\\', '|', '?', '*':
+			return '_'
+		}
+		if r < 32 {
+			return -1
+		}
+		return r
+	}, title)
+
+	name = strings.Trim(name, " .")
+	if name == "" {
+		name = "Tarkov Account"
+	}
+	return name
+}
+
+// SetURIScheme sets and saves whether the tarkovswitch:// handler is registered
+func SetURIScheme(enabled bool) error {
+	settings := GetSettings()
+	settings.URIScheme = enabled
+	return SaveSettings(settings)
+}
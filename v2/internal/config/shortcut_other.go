@@ -0,0 +1,67 @@
+//go:build !windows
+
+package config
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// CreateShortcut creates a desktop entry on the user's desktop that starts
+// the app with args and returns its path
+func CreateShortcut(title string, args []string) (string, error) {
+	exePath, err := os.Executable()
+	if err != nil {
+		return "", err
+	}
+
+	desktop := desktopDir()
+	if desktop == "" {
+		return "", os.ErrNotExist
+	}
+	path := filepath.Join(desktop, shortcutFileName(title)+".desktop")
+
+	quoted := make([]string, len(args))
+	for i, arg := range args {
+		quoted[i] = desktopQuote(arg)
+	}
+
+	entry := "[Desktop Entry]\n" +
+		"Type=Application\n" +
+		"Name=" + strings.ReplaceAll(title, "\n", " ") + "\n" +
+		"Exec=" + desktopQuote(exePath) + " " + strings.Join(quoted, " ") + "\n" +
+		"Terminal=false\n"
+
+	if err := os.MkdirAll(desktop, 0755); err != nil {
+		return "", err
+	}
+	// Desktops only launch entries on the desktop that are executable
+	if err := os.WriteFile(path, []byte(entry), 0755); err != nil {
+		return "", err
+	}
+	return path, nil
+}
+
+// desktopDir returns the XDG desktop directory
+func desktopDir() string {
+	if dir := os.Getenv("XDG_DESKTOP_DIR"); filepath.IsAbs(dir) {
+		return dir
+	}
+	if out, err := exec.Command("xdg-user-dir", "DESKTOP").Output(); err == nil {
+		if dir := strings.TrimSpace(string(out)); filepath.IsAbs(dir) {
+			return dir
+		}
+	}
+	if home := homeDir(); home != "" {
+		return filepath.Join(home, "Desktop")
+	}
+	return ""
+}
+
+// desktopQuote quotes an Exec argument as the desktop entry spec requires
+func desktopQuote(arg string) string {
+	r := strings.NewReplacer(`\`, `\\`, `"`, `\"`, "`", "\\`", "$", `\$`, "%", "%%")
+	return `"` + r.Replace(arg) + `"`
+}
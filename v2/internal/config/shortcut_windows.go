@@ -0,0 +1,42 @@
+package config
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"syscall"
+)
+
+// CreateShortcut creates a desktop shortcut (.lnk) that starts the app with args
+// and returns its path. The link is written by WScript.Shell through PowerShell,
+// which avoids driving IShellLink over COM by hand.
+func CreateShortcut(title string, args []string) (string, error) {
+	exePath, err := os.Executable()
+	if err != nil {
+		return "", err
+	}
+
+	script := "$d = [Environment]::GetFolderPath('Desktop');" +
+		"$p = Join-Path $d " + psQuote(shortcutFileName(title)+".lnk") + ";" +
+		"$s = (New-Object -ComObject WScript.Shell).CreateShortcut($p);" +
+		"$s.TargetPath = " + psQuote(exePath) + ";" +
+		"$s.Arguments = " + psQuote(strings.Join(args, " ")) + ";" +
+		"$s.WorkingDirectory = " + psQuote(filepath.Dir(exePath)) + ";" +
+		"$s.IconLocation = " + psQuote(exePath+",0") + ";" +
+		"$s.Save();" +
+		"Write-Output $p"
+
+	cmd := exec.Command("powershell", "-NoProfile", "-NonInteractive", "-Command", script)
+	cmd.SysProcAttr = &syscall.SysProcAttr{HideWindow: true}
+	out, err := cmd.Output()
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// psQuote quotes s as a PowerShell single-quoted string
+func psQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
@@ -0,0 +1,40 @@
+package config
+
+import "errors"
+
+// Switch policy modes
+const (
+	PolicyOff     = "off"     // no cooldown checks
+	PolicyWarn    = "warn"    // switch, but add a warning
+	PolicyConfirm = "confirm" // require an explicit confirmation
+)
+
+// SwitchPolicy limits how often an account is switched into. Switching too
+// often on one machine tends to trigger BSG captchas and re-auth.
+type SwitchPolicy struct {
+	Mode          string `json:"mode"`
+	MaxSwitches   int    `json:"maxSwitches"`   // switches into one account allowed per window
+	WindowMinutes int    `json:"windowMinutes"` // length of the window
+}
+
+// DefaultSwitchPolicy warns after three switches into an account within an hour
+var DefaultSwitchPolicy = SwitchPolicy{
+	Mode:          PolicyWarn,
+	MaxSwitches:   3,
+	WindowMinutes: 60,
+}
+
+// SetSwitchPolicy validates and saves the switch policy
+func SetSwitchPolicy(policy SwitchPolicy) error {
+	switch policy.Mode {
+	case PolicyOff, PolicyWarn, PolicyConfirm:
+	default:
+		return errors.New("unknown switch policy mode: " + policy.Mode)
+	}
+	if policy.Mode != PolicyOff && (policy.MaxSwitches < 1 || policy.WindowMinutes < 1) {
+		return errors.New("switch policy needs at least one switch per minute window")
+	}
+	settings := GetSettings()
+	settings.SwitchPolicy = policy
+	return SaveSettings(settings)
+}
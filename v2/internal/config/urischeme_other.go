@@ -0,0 +1,77 @@
+//go:build !windows
+
+package config
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+)
+
+// uriSchemeDesktopFile is the name of the XDG entry handling tarkovswitch:// links
+const uriSchemeDesktopFile = "tarkovswitch-handler.desktop"
+
+// uriSchemeFile returns the path of the handler's desktop entry
+func uriSchemeFile() (string, error) {
+	dataHome := xdgDir("XDG_DATA_HOME", homeDir(), ".local", "share")
+	if dataHome == "" {
+		return "", os.ErrNotExist
+	}
+	return filepath.Join(dataHome, "applications", uriSchemeDesktopFile), nil
+}
+
+// ApplyURIScheme installs or removes the x-scheme-handler desktop entry for tarkovswitch://
+func ApplyURIScheme(enabled bool) error {
+	path, err := uriSchemeFile()
+	if err != nil {
+		return err
+	}
+
+	if !enabled {
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		return nil
+	}
+
+	exePath, err := os.Executable()
+	if err != nil {
+		return err
+	}
+
+	entry := "[Desktop Entry]\n" +
+		"Type=Application\n" +
+		"Name=Tarkov Account Switcher\n" +
+		"Exec=\"" + exePath + "\" %u\n" +
+		"MimeType=x-scheme-handler/" + URIScheme + ";\n" +
+		"NoDisplay=true\n"
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, []byte(entry), 0644); err != nil {
+		return err
+	}
+
+	// Make it the default handler; desktops without xdg-mime pick it up from MimeType
+	if xdgMime, err := exec.LookPath("xdg-mime"); err == nil {
+		return exec.Command(xdgMime, "default", uriSchemeDesktopFile, "x-scheme-handler/"+URIScheme).Run()
+	}
+	return nil
+}
+
+// IsURISchemeRegistered checks if the handler desktop entry exists
+func IsURISchemeRegistered() bool {
+	path, err := uriSchemeFile()
+	if err != nil {
+		return false
+	}
+	_, err = os.Stat(path)
+	return err == nil
+}
+
+// homeDir returns the user's home directory or ""
+func homeDir() string {
+	home, _ := os.UserHomeDir()
+	return home
+}
@@ -0,0 +1,75 @@
+package config
+
+import (
+	"os"
+	"syscall"
+	"unsafe"
+)
+
+// uriSchemeKey is the per-user class key of the tarkovswitch:// handler
+const uriSchemeKey = `Software\Classes\` + URIScheme
+
+var (
+	regCreateKeyExW = advapi32.NewProc("RegCreateKeyExW")
+	regDeleteTreeW  = advapi32.NewProc("RegDeleteTreeW")
+)
+
+// ApplyURIScheme registers or removes the tarkovswitch:// handler for the current user
+func ApplyURIScheme(enabled bool) error {
+	if !enabled {
+		keyPath, _ := syscall.UTF16PtrFromString(uriSchemeKey)
+		ret, _, _ := regDeleteTreeW.Call(hkeyCurrentUser, uintptr(unsafe.Pointer(keyPath)))
+		if ret != 0 && syscall.Errno(ret) != syscall.ERROR_FILE_NOT_FOUND {
+			return syscall.Errno(ret)
+		}
+		return nil
+	}
+
+	exePath, err := os.Executable()
+	if err != nil {
+		return err
+	}
+
+	if err := setRegistryString(uriSchemeKey, "", "URL:Tarkov Account Switcher"); err != nil {
+		return err
+	}
+	if err := setRegistryString(uriSchemeKey, "URL Protocol", ""); err != nil {
+		return err
+	}
+	return setRegistryString(uriSchemeKey+`\shell\open\command`, "", `"`+exePath+`" "%1"`)
+}
+
+// IsURISchemeRegistered checks if the tarkovswitch:// handler key exists
+func IsURISchemeRegistered() bool {
+	keyPath, _ := syscall.UTF16PtrFromString(uriSchemeKey)
+
+	var hKey uintptr
+	ret, _, _ := regOpenKeyExW.Call(hkeyCurrentUser, uintptr(unsafe.Pointer(keyPath)), 0, keyRead, uintptr(unsafe.Pointer(&hKey)))
+	if ret != 0 {
+		return false
+	}
+	regCloseKey.Call(hKey)
+	return true
+}
+
+// setRegistryString creates the key below HKCU if needed and sets a string value.
+// An empty name sets the key's default value.
+func setRegistryString(key, name, value string) error {
+	keyPath, _ := syscall.UTF16PtrFromString(key)
+	valueName, _ := syscall.UTF16PtrFromString(name)
+	valueData, _ := syscall.UTF16FromString(value)
+
+	var hKey uintptr
+	ret, _, _ := regCreateKeyExW.Call(hkeyCurrentUser, uintptr(unsafe.Pointer(keyPath)), 0, 0, 0, keyWrite, 0, uintptr(unsafe.Pointer(&hKey)), 0)
+	if ret != 0 {
+		return syscall.Errno(ret)
+	}
+	defer regCloseKey.Call(hKey)
+
+	dataBytes := len(valueData) * 2 // UTF-16 = 2 bytes per char
+	ret, _, _ = regSetValueExW.Call(hKey, uintptr(unsafe.Pointer(valueName)), 0, regSZ, uintptr(unsafe.Pointer(&valueData[0])), uintptr(dataBytes))
+	if ret != 0 {
+		return syscall.Errno(ret)
+	}
+	return nil
+}
@@ -0,0 +1,81 @@
+package doctor
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"time"
+
+	"tarkov-account-switcher/internal/accounts"
+	"tarkov-account-switcher/internal/config"
+	"tarkov-account-switcher/internal/logging"
+	"tarkov-account-switcher/internal/redact"
+)
+
+// WriteBundle writes a support bundle zip to path: the report, the log files,
+// the last switch traces and settings.json, all passed through redact.
+// Accounts, the key and the API token are never included.
+func WriteBundle(path string, report Report) error {
+	out, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0600)
+	if err != nil {
+		return err
+	}
+	zw := zip.NewWriter(out)
+
+	reportData, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		out.Close()
+		return err
+	}
+	if err := addFile(zw, "report.json", reportData, report.Time); err != nil {
+		out.Close()
+		return err
+	}
+
+	if data, err := os.ReadFile(config.GetPaths().SettingsFile); err == nil {
+		if err := addFile(zw, "settings.json", data, report.Time); err != nil {
+			out.Close()
+			return err
+		}
+	}
+
+	if data, err := os.ReadFile(accounts.TracesFile()); err == nil {
+		if err := addFile(zw, "switch-traces.json", data, report.Time); err != nil {
+			out.Close()
+			return err
+		}
+	}
+
+	for _, logFile := range logging.Files() {
+		data, err := os.ReadFile(logFile)
+		if err != nil {
+			continue // rotated away since Files
+		}
+		if err := addFile(zw, "logs/"+filepath.Base(logFile), data, report.Time); err != nil {
+			out.Close()
+			return err
+		}
+	}
+
+	if err := zw.Close(); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
+
+// addFile stores data in the zip after redacting it. Logs are redacted when
+// written already; this also covers lines from older versions.
+func addFile(zw *zip.Writer, name string, data []byte, modified time.Time) error {
+	w, err := zw.CreateHeader(&zip.FileHeader{
+		Name:     name,
+		Method:   zip.Deflate,
+		Modified: modified,
+	})
+	if err != nil {
+		return err
+	}
+	_, err = w.Write([]byte(redact.String(string(data))))
+	return err
+}
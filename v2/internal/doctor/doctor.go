@@ -0,0 +1,292 @@
+// Package doctor runs the diagnostics support asks for first: launcher path,
+// launcher settings, key and sessions, Game.ini and file permissions. The
+// checks only read state through the config, launcher and accounts packages.
+package doctor
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+
+	"tarkov-account-switcher/internal/accounts"
+	"tarkov-account-switcher/internal/config"
+	"tarkov-account-switcher/internal/launcher"
+	"tarkov-account-switcher/internal/updater"
+)
+
+// Status is the outcome of a check
+type Status string
+
+const (
+	StatusOK   Status = "ok"
+	StatusWarn Status = "warn" // works, but something looks off
+	StatusFail Status = "fail" // switching will not work
+)
+
+// Check IDs, stable for scripts and translations
+const (
+	CheckDataDir          = "data-dir"
+	CheckSettings         = "settings"
+	CheckLauncherPath     = "launcher-path"
+	CheckLauncherSettings = "launcher-settings"
+	CheckLauncherWritable = "launcher-writable"
+	CheckLauncherRunning  = "launcher-running"
+	CheckGameIni          = "game-ini"
+	CheckKey              = "key"
+	CheckAccounts         = "accounts"
+	CheckSessions         = "sessions"
+	CheckWine             = "wine"
+)
+
+// Check is the result of one diagnostic
+type Check struct {
+	ID     string `json:"id"`
+	Status Status `json:"status"`
+	Detail string `json:"detail,omitempty"`
+}
+
+// Report is the result of Run
+type Report struct {
+	Version       string    `json:"version"`
+	OS            string    `json:"os"`
+	Wine          bool      `json:"wine"`
+	Time          time.Time `json:"time"`
+	DataDir       string    `json:"dataDir"`
+	DataDirSource string    `json:"dataDirSource"`
+	Checks        []Check   `json:"checks"`
+
+	LastSwitch *accounts.Trace `json:"lastSwitch,omitempty"` // timed steps of the latest switch
+}
+
+// Failed reports whether any check failed
+func (r Report) Failed() bool {
+	for _, c := range r.Checks {
+		if c.Status == StatusFail {
+			return true
+		}
+	}
+	return false
+}
+
+// Run executes all checks
+func Run() Report {
+	paths := config.GetPaths()
+	report := Report{
+		Version:       updater.CurrentVersion,
+		OS:            runtime.GOOS + "/" + runtime.GOARCH,
+		Wine:          config.IsWine(),
+		Time:          time.Now(),
+		DataDir:       paths.DataDir,
+		DataDirSource: paths.DataDirSource,
+	}
+
+	report.Checks = []Check{
+		checkDataDir(),
+		checkSettings(),
+	}
+	if config.IsWine() {
+		report.Checks = append(report.Checks, checkWine())
+	}
+	report.Checks = append(report.Checks,
+		checkLauncherPath(),
+		checkLauncherSettings(),
+		checkLauncherWritable(),
+		checkLauncherRunning(),
+		checkGameIni(),
+		checkKey(),
+		checkAccounts(),
+		checkSessions(),
+	)
+
+	if traces, err := accounts.RecentTraces(); err == nil && len(traces) > 0 {
+		report.LastSwitch = &traces[0]
+	}
+	return report
+}
+
+func ok(id, detail string) Check   { return Check{ID: id, Status: StatusOK, Detail: detail} }
+func warn(id, detail string) Check { return Check{ID: id, Status: StatusWarn, Detail: detail} }
+func fail(id, detail string) Check { return Check{ID: id, Status: StatusFail, Detail: detail} }
+
+func checkDataDir() Check {
+	dir := config.GetPaths().DataDir
+	if dir == "" {
+		return fail(CheckDataDir, "no data directory could be resolved")
+	}
+	probe, err := os.CreateTemp(dir, ".doctor-*")
+	if err != nil {
+		return fail(CheckDataDir, fmt.Sprintf("%s is not writable: %v", dir, err))
+	}
+	probe.Close()
+	os.Remove(probe.Name())
+	return ok(CheckDataDir, dir)
+}
+
+func checkSettings() Check {
+	path := config.GetPaths().SettingsFile
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return ok(CheckSettings, "not saved yet, using defaults")
+	}
+	if err != nil {
+		return fail(CheckSettings, err.Error())
+	}
+	var settings map[string]interface{}
+	if err := json.Unmarshal(data, &settings); err != nil {
+		return fail(CheckSettings, fmt.Sprintf("%s does not parse: %v", path, err))
+	}
+	return ok(CheckSettings, path)
+}
+
+func checkWine() Check {
+	prefix := config.WinePrefix()
+	if info, err := os.Stat(filepath.Join(prefix, "drive_c")); err != nil || !info.IsDir() {
+		return fail(CheckWine, fmt.Sprintf("%s is not a Wine prefix", prefix))
+	}
+	command := config.GetSettings().Wine.Command
+	if len(command) == 0 {
+		command = []string{"wine"}
+	}
+	if _, err := exec.LookPath(command[0]); err != nil {
+		return fail(CheckWine, fmt.Sprintf("%s not found in PATH", command[0]))
+	}
+	return ok(CheckWine, fmt.Sprintf("%s (%s)", prefix, strings.Join(command, " ")))
+}
+
+func checkLauncherPath() Check {
+	configured := config.GetSettings().LauncherPath
+	if configured == "" {
+		return fail(CheckLauncherPath, "no launcher path set")
+	}
+	path := config.HostPath(configured)
+	info, err := os.Stat(path)
+	if err != nil {
+		return fail(CheckLauncherPath, fmt.Sprintf("%s not found", path))
+	}
+	if info.IsDir() {
+		return fail(CheckLauncherPath, fmt.Sprintf("%s is a directory", path))
+	}
+	return ok(CheckLauncherPath, path)
+}
+
+func checkLauncherSettings() Check {
+	path := config.LauncherSettingsPath()
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		return fail(CheckLauncherSettings, fmt.Sprintf("%s not found - start the launcher once", path))
+	}
+	settings, err := launcher.ReadLauncherSettings()
+	if err != nil {
+		return fail(CheckLauncherSettings, fmt.Sprintf("%s does not parse: %v", path, err))
+	}
+	current := launcher.ReadLogin(settings)
+	login, at := current.Email, current.AccessToken
+	if login == "" {
+		return ok(CheckLauncherSettings, "no account logged in")
+	}
+	state := "logged out"
+	if at != "" {
+		state = "session present"
+	}
+	return ok(CheckLauncherSettings, fmt.Sprintf("%s, %s", config.HideEmail(login), state))
+}
+
+// checkLauncherWritable opens the settings file for writing without
+// truncating it; switching rewrites it on every run
+func checkLauncherWritable() Check {
+	path := config.LauncherSettingsPath()
+	f, err := os.OpenFile(path, os.O_WRONLY, 0)
+	if os.IsNotExist(err) {
+		dir := filepath.Dir(path)
+		if _, err := os.Stat(dir); err != nil {
+			return warn(CheckLauncherWritable, fmt.Sprintf("%s does not exist yet", dir))
+		}
+		return ok(CheckLauncherWritable, "created on first switch")
+	}
+	if err != nil {
+		return fail(CheckLauncherWritable, err.Error())
+	}
+	f.Close()
+	return ok(CheckLauncherWritable, path)
+}
+
+func checkLauncherRunning() Check {
+	if launcher.IsLauncherRunning() {
+		return ok(CheckLauncherRunning, "running")
+	}
+	return ok(CheckLauncherRunning, "not running")
+}
+
+func checkGameIni() Check {
+	path := launcher.GetGameSettingsPath()
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return warn(CheckGameIni, fmt.Sprintf("%s not found - start the game once", path))
+	}
+	if err != nil {
+		return fail(CheckGameIni, err.Error())
+	}
+	var settings map[string]interface{}
+	if err := json.Unmarshal(data, &settings); err != nil {
+		return warn(CheckGameIni, fmt.Sprintf("%s does not parse: %v", path, err))
+	}
+	return ok(CheckGameIni, path)
+}
+
+func checkKey() Check {
+	path := config.GetPaths().KeyFile
+	info, err := os.Stat(path)
+	if os.IsNotExist(err) {
+		return ok(CheckKey, "not created yet")
+	}
+	if err != nil {
+		return fail(CheckKey, err.Error())
+	}
+	if info.Size() != 32 {
+		return fail(CheckKey, fmt.Sprintf("%s has %d bytes, expected 32", path, info.Size()))
+	}
+	if runtime.GOOS != "windows" && info.Mode().Perm()&0077 != 0 {
+		return warn(CheckKey, fmt.Sprintf("%s has mode %04o, expected 0600", path, info.Mode().Perm()))
+	}
+	return ok(CheckKey, path)
+}
+
+// checkAccounts parses accounts.json directly; GetAccounts would migrate
+// legacy sessions and create a key
+func checkAccounts() Check {
+	path := config.GetPaths().AccountsFile
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return ok(CheckAccounts, "no accounts yet")
+	}
+	if err != nil {
+		return fail(CheckAccounts, err.Error())
+	}
+	var stored []accounts.Account
+	if err := json.Unmarshal(data, &stored); err != nil {
+		return fail(CheckAccounts, fmt.Sprintf("%s does not parse: %v", path, err))
+	}
+	return ok(CheckAccounts, fmt.Sprintf("%d accounts", len(stored)))
+}
+
+func checkSessions() Check {
+	checks, err := accounts.CheckSessions()
+	if err != nil {
+		return fail(CheckSessions, err.Error())
+	}
+	var broken []string
+	for _, c := range checks {
+		if c.Err != nil {
+			broken = append(broken, fmt.Sprintf("%s (%v)", c.AccountName, c.Err))
+		}
+	}
+	if len(broken) > 0 {
+		return fail(CheckSessions, fmt.Sprintf("%d of %d sessions not decryptable: %s",
+			len(broken), len(checks), strings.Join(broken, ", ")))
+	}
+	return ok(CheckSessions, fmt.Sprintf("%d sessions decrypt", len(checks)))
+}
@@ -0,0 +1,86 @@
+// Package events is an in-process bus for switch, capture and update events.
+// Subscribers such as the API's WebSocket stream get a copy of every event.
+// Events never carry session data or tokens.
+package events
+
+import (
+	"sync"
+	"time"
+)
+
+// Event types
+const (
+	SwitchStarted   = "switch.started"
+	SwitchStep      = "switch.step"
+	SwitchStepDone  = "switch.step.done"
+	SwitchSucceeded = "switch.succeeded"
+	SwitchFailed    = "switch.failed"
+	SessionCaptured = "session.captured"
+	WatcherTimeout  = "watcher.timeout"
+	GameNotStarted  = "game.not_started"
+	UpdateAvailable = "update.available"
+)
+
+// Event is one typed event. Only the fields that apply to the type are set.
+type Event struct {
+	Type        string    `json:"type"`
+	Time        time.Time `json:"time"`
+	AccountID   string    `json:"accountId,omitempty"`
+	AccountName string    `json:"accountName,omitempty"`
+	Origin      string    `json:"origin,omitempty"`     // switch.*: action or api; empty for the window
+	Step        string    `json:"step,omitempty"`       // switch.step, switch.step.done
+	DurationMs  int64     `json:"durationMs,omitempty"` // switch.step.done, switch.succeeded, switch.failed
+	HasSession  bool      `json:"hasSession,omitempty"` // switch.succeeded: auto-login
+	Message     string    `json:"message,omitempty"`
+	Code        string    `json:"code,omitempty"` // switch.failed: apperror code
+	Error       string    `json:"error,omitempty"`
+	Game        string    `json:"game,omitempty"`    // game.not_started
+	Version     string    `json:"version,omitempty"` // update.available
+	URL         string    `json:"url,omitempty"`     // update.available: release page
+	Beta        bool      `json:"beta,omitempty"`    // update.available
+}
+
+// subscriberBuffer is how many events a slow subscriber may lag behind
+// before further events are dropped for it
+const subscriberBuffer = 64
+
+var (
+	mu          sync.Mutex
+	subscribers = make(map[chan Event]struct{})
+)
+
+// Publish sends an event to all subscribers without blocking
+func Publish(event Event) {
+	if event.Time.IsZero() {
+		event.Time = time.Now()
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	for ch := range subscribers {
+		select {
+		case ch <- event:
+		default: // subscriber is not keeping up
+		}
+	}
+}
+
+// Subscribe returns a channel receiving all future events and a function
+// that ends the subscription and closes the channel
+func Subscribe() (<-chan Event, func()) {
+	ch := make(chan Event, subscriberBuffer)
+
+	mu.Lock()
+	subscribers[ch] = struct{}{}
+	mu.Unlock()
+
+	var once sync.Once
+	return ch, func() {
+		once.Do(func() {
+			mu.Lock()
+			delete(subscribers, ch)
+			mu.Unlock()
+			close(ch)
+		})
+	}
+}
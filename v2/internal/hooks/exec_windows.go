@@ -0,0 +1,11 @@
+package hooks
+
+import (
+	"os/exec"
+	"syscall"
+)
+
+// hideWindow keeps console hooks from flashing a window
+func hideWindow(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{HideWindow: true}
+}
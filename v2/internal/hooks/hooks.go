@@ -0,0 +1,129 @@
+package hooks
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+
+	"tarkov-account-switcher/internal/apperror"
+	"tarkov-account-switcher/internal/config"
+)
+
+// Event describes what happened. It is passed to hook commands as
+// TAS_* environment variables; the email is always masked.
+type Event struct {
+	Name        string // one of the config.Hook* events
+	AccountID   string
+	AccountName string
+	Email       string
+	Result      string // short outcome, e.g. "auto-login" or "failed"
+	Error       string
+}
+
+// env returns the environment for a hook command
+func (e Event) env() []string {
+	return append(os.Environ(),
+		"TAS_EVENT="+e.Name,
+		"TAS_ACCOUNT_ID="+e.AccountID,
+		"TAS_ACCOUNT_NAME="+e.AccountName,
+		"TAS_ACCOUNT_EMAIL="+config.HideEmail(e.Email),
+		"TAS_RESULT="+e.Result,
+		"TAS_ERROR="+e.Error,
+	)
+}
+
+// Run runs all hooks configured for the event one after another and waits
+// for them. It returns an error only if a vetoing beforeSwitch hook failed
+// or ctx ended, which kills the running hook and skips the rest; other
+// failures are ignored so a broken hook never breaks a switch.
+func Run(ctx context.Context, event Event) error {
+	for _, hook := range config.GetSettings().Hooks {
+		if hook.Event != event.Name || len(hook.Command) == 0 {
+			continue
+		}
+
+		err := runHook(ctx, hook, event)
+		if ctx.Err() != nil {
+			slog.Warn("hook cancelled", "event", event.Name, "command", hook.Command[0], "err", ctx.Err())
+			return apperror.FromContext(ctx.Err())
+		}
+		if err != nil {
+			slog.Warn("hook failed", "event", event.Name, "command", hook.Command[0], "err", err)
+		} else {
+			slog.Info("hook ran", "event", event.Name, "command", hook.Command[0])
+		}
+		if err != nil && hook.Veto && event.Name == config.HookBeforeSwitch {
+			return errors.New("switch cancelled by hook " + hook.Command[0] + ": " + err.Error())
+		}
+	}
+	return nil
+}
+
+// running tracks hooks started by Fire
+var running sync.WaitGroup
+
+// Fire runs the event's hooks in the background. They outlive the switch
+// that fired them; Wait lets them finish before exiting.
+func Fire(event Event) {
+	running.Add(1)
+	go func() {
+		defer running.Done()
+		Run(context.Background(), event)
+	}()
+}
+
+// Wait blocks until all hooks started by Fire have finished.
+// Used before exiting so background hooks are not cut off.
+func Wait() {
+	running.Wait()
+}
+
+// runHook runs a single hook command with its timeout, killing it early
+// when parent ends
+func runHook(parent context.Context, hook config.Hook, event Event) error {
+	timeout := hook.Timeout
+	if timeout <= 0 {
+		timeout = config.DefaultHookTimeout
+	}
+
+	ctx, cancel := context.WithTimeout(parent, time.Duration(timeout)*time.Second)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, hook.Command[0], hook.Command[1:]...)
+	cmd.Env = event.env()
+	cmd.WaitDelay = time.Second // don't wait on pipes held by leftover children
+	hideWindow(cmd)
+
+	var output bytes.Buffer
+	cmd.Stdout = &output
+	cmd.Stderr = &output
+
+	err := cmd.Run()
+	if ctx.Err() == context.DeadlineExceeded {
+		return errors.New("timed out")
+	}
+	if err != nil {
+		if msg := lastLine(output.String()); msg != "" {
+			return errors.New(msg)
+		}
+		return err
+	}
+	return nil
+}
+
+// lastLine returns the last non-empty line of a hook's output, which is
+// usually the reason it failed
+func lastLine(output string) string {
+	lines := strings.Split(strings.TrimSpace(output), "\n")
+	line := strings.TrimSpace(lines[len(lines)-1])
+	if len(line) > 200 {
+		line = line[:200]
+	}
+	return line
+}
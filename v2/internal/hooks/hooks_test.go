@@ -0,0 +1,95 @@
+package hooks
+
+import (
+	"context"
+	"os"
+	"runtime"
+	"testing"
+	"time"
+
+	"tarkov-account-switcher/internal/apperror"
+	"tarkov-account-switcher/internal/config"
+)
+
+// TestMain keeps the tests away from the real data directory
+func TestMain(m *testing.M) {
+	dir, err := os.MkdirTemp("", "tarkov-switcher-test-")
+	if err != nil {
+		panic(err)
+	}
+	os.Setenv(config.DataDirEnv, dir)
+
+	code := m.Run()
+	os.RemoveAll(dir)
+	os.Exit(code)
+}
+
+func TestRunStopsWithContext(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("needs sleep")
+	}
+	settings := config.GetSettings()
+	settings.Hooks = []config.Hook{
+		{Event: config.HookBeforeSwitch, Command: []string{"sleep", "30"}},
+		{Event: config.HookBeforeSwitch, Command: []string{"sleep", "30"}},
+	}
+	defer func() { settings.Hooks = nil }()
+
+	tests := []struct {
+		name string
+		ctx  func() (context.Context, context.CancelFunc)
+		want apperror.Code
+	}{
+		{"cancelled", func() (context.Context, context.CancelFunc) {
+			ctx, cancel := context.WithCancel(context.Background())
+			time.AfterFunc(100*time.Millisecond, cancel)
+			return ctx, cancel
+		}, apperror.Cancelled},
+		{"deadline", func() (context.Context, context.CancelFunc) {
+			return context.WithTimeout(context.Background(), 100*time.Millisecond)
+		}, apperror.TimedOut},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := tt.ctx()
+			defer cancel()
+
+			start := time.Now()
+			err := Run(ctx, Event{Name: config.HookBeforeSwitch})
+			if got := apperror.CodeOf(err); got != tt.want {
+				t.Errorf("Run() = %v, want code %s", err, tt.want)
+			}
+			if elapsed := time.Since(start); elapsed > 5*time.Second {
+				t.Errorf("Run() took %v, want the hooks killed", elapsed)
+			}
+		})
+	}
+}
+
+func TestRunVeto(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("needs false")
+	}
+	settings := config.GetSettings()
+	defer func() { settings.Hooks = nil }()
+
+	tests := []struct {
+		name    string
+		hook    config.Hook
+		wantErr bool
+	}{
+		{"veto", config.Hook{Event: config.HookBeforeSwitch, Command: []string{"false"}, Veto: true}, true},
+		{"no veto", config.Hook{Event: config.HookBeforeSwitch, Command: []string{"false"}}, false},
+		{"passes", config.Hook{Event: config.HookBeforeSwitch, Command: []string{"true"}, Veto: true}, false},
+		{"other event", config.Hook{Event: config.HookLoggedIn, Command: []string{"false"}, Veto: true}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			settings.Hooks = []config.Hook{tt.hook}
+			err := Run(context.Background(), Event{Name: config.HookBeforeSwitch})
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Run() = %v, want error %v", err, tt.wantErr)
+			}
+		})
+	}
+}
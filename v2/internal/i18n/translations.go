@@ -51,6 +51,9 @@ const (
 	// Theme
 	LabelTheme = "labelTheme"
 
+	// Data directory
+	LabelDataDir = "labelDataDir"
+
 	// Autostart
 	LabelAutoStart = "labelAutoStart"
 	AutoStartHelp  = "autoStartHelp"
@@ -62,9 +65,115 @@ const (
 	SwitchAutoLogin  = "switchAutoLogin"
 	SwitchManualLogin = "switchManualLogin"
 
+	// Post-switch verification
+	VerifyLoggedIn = "verifyLoggedIn"
+	VerifyRejected = "verifyRejected"
+
+	// Switch cooldown
+	CooldownWarning = "cooldownWarning"
+	CooldownConfirm = "cooldownConfirm"
+	StatusCooldown  = "statusCooldown"
+	DurationMinutes = "durationMinutes"
+	WarnCefNotSaved = "warnCefNotSaved"
+
+	// Forwarded actions (shortcuts, second instance)
+	NotifyTitle        = "notifyTitle"
+	NotifySwitched     = "notifySwitched"
+	NotifySwitchFailed = "notifySwitchFailed"
+
+	// Desktop notifications
+	NotifyCaptured       = "notifyCaptured"
+	NotifyCaptureTimeout = "notifyCaptureTimeout"
+	NotifyGameNotStarted = "notifyGameNotStarted"
+	NotifyExpiring       = "notifyExpiring"
+	LabelNotifications   = "labelNotifications"
+	NotifyOptCaptured    = "notifyOptCaptured"
+	NotifyOptTimeout     = "notifyOptTimeout"
+	NotifyOptFailed      = "notifyOptFailed"
+	NotifyOptExpiring    = "notifyOptExpiring"
+
+	// Shortcuts and links
+	BtnShortcut           = "btnShortcut"
+	StatusShortcutCreated = "statusShortcutCreated"
+	LabelURIScheme        = "labelUriScheme"
+	URISchemeHelp         = "uriSchemeHelp"
+
+	// Local HTTP API
+	LabelAPI          = "labelApi"
+	APIHelp           = "apiHelp"
+	BtnCopyToken      = "btnCopyToken"
+	StatusTokenCopied = "statusTokenCopied"
+	APIStartFailed    = "apiStartFailed"
+
 	// Tray Menu
-	TrayOpen = "trayOpen"
-	TrayQuit = "trayQuit"
+	TrayOpen       = "trayOpen"
+	TrayQuit       = "trayQuit"
+	TrayNoSession  = "trayNoSession"
+	TrayNoAccounts = "trayNoAccounts"
+
+	// Tray Tooltip
+	TipLoggedIn  = "tipLoggedIn"
+	TipLoggedOut = "tipLoggedOut"
+	TipWatching  = "tipWatching"
+	TipFailed    = "tipFailed"
+	TipUpdate    = "tipUpdate"
+
+	// Diagnostics
+	LabelDiagnostics       = "labelDiagnostics"
+	DiagnosticsHelp        = "diagnosticsHelp"
+	BtnRunDiagnostics      = "btnRunDiagnostics"
+	BtnSupportBundle       = "btnSupportBundle"
+	StatusBundleSaved      = "statusBundleSaved"
+	DoctorDataDir          = "doctorDataDir"
+	DoctorSettings         = "doctorSettings"
+	DoctorWine             = "doctorWine"
+	DoctorLauncherPath     = "doctorLauncherPath"
+	DoctorLauncherSettings = "doctorLauncherSettings"
+	DoctorLauncherWritable = "doctorLauncherWritable"
+	DoctorLauncherRunning  = "doctorLauncherRunning"
+	DoctorGameIni          = "doctorGameIni"
+	DoctorKey              = "doctorKey"
+	DoctorAccounts         = "doctorAccounts"
+	DoctorSessions         = "doctorSessions"
+
+	// Errors (see package apperror)
+	ErrAccountNotFound      = "errAccountNotFound"
+	ErrAccountsUnreadable   = "errAccountsUnreadable"
+	ErrAccountsUnwritable   = "errAccountsUnwritable"
+	ErrUnknownGame          = "errUnknownGame"
+	ErrGameRunning          = "errGameRunning"
+	ErrLauncherNotFound     = "errLauncherNotFound"
+	ErrLauncherStartFailed  = "errLauncherStartFailed"
+	ErrKillTimeout          = "errKillTimeout"
+	ErrSettingsUnreadable   = "errSettingsUnreadable"
+	ErrSettingsUnwritable   = "errSettingsUnwritable"
+	ErrSessionUndecryptable = "errSessionUndecryptable"
+	ErrNotLoggedIn          = "errNotLoggedIn"
+	ErrNoAccountForLogin    = "errNoAccountForLogin"
+	ErrHookVeto             = "errHookVeto"
+	ErrProfileFailed        = "errProfileFailed"
+	ErrBusy                 = "errBusy"
+	ErrCancelled            = "errCancelled"
+	ErrTimedOut             = "errTimedOut"
+	ErrAccountAmbiguous     = "errAccountAmbiguous"
+	ErrNoAccountGiven       = "errNoAccountGiven"
+	ErrImportVersion        = "errImportVersion"
+	ErrImportInvalid        = "errImportInvalid"
+	ErrInvalidArguments     = "errInvalidArguments"
+	ErrInvalidLink          = "errInvalidLink"
+	ErrInvalidCacheTarget   = "errInvalidCacheTarget"
+	ErrNoLaunchArgs         = "errNoLaunchArgs"
+	ErrGameNotStarted       = "errGameNotStarted"
+
+	// Switch steps
+	StepSaveSession     = "stepSaveSession"
+	StepKillLauncher    = "stepKillLauncher"
+	StepClearCache      = "stepClearCache"
+	StepRestoreProfiles = "stepRestoreProfiles"
+	StepRestoreSession  = "stepRestoreSession"
+	StepResetSession    = "stepResetSession"
+	StepStartLauncher   = "stepStartLauncher"
+	StepTotal           = "stepTotal"
 
 	// Status Messages
 	StatusFillFields     = "statusFillFields"
@@ -120,6 +229,7 @@ var translations = map[string]map[string]string{
 		LabelStreamerMode:   "Streamer Modus",
 		StreamerModeHelp:    "Versteckt Email-Adressen mit ****",
 		LabelTheme:          "Design / Theme",
+		LabelDataDir:        "Datenordner",
 		LabelAutoStart:      "Autostart mit Windows",
 		AutoStartHelp:       "Startet die App automatisch beim Windows-Login",
 		BtnQuit:             "Beenden",
@@ -128,9 +238,115 @@ var translations = map[string]map[string]string{
 		SwitchAutoLogin:  "Launcher gestartet - Auto-Login aktiv!",
 		SwitchManualLogin: "Bitte einloggen - Session wird automatisch gespeichert!",
 
+		// Post-switch verification
+		VerifyLoggedIn: "Eingeloggt als {name}",
+		VerifyRejected: "Session abgelehnt - bitte neu einloggen, die Session wird automatisch gespeichert!",
+
+		// Switch cooldown
+		CooldownWarning: "{name} wurde in den letzten {minutes} Minuten {count}x gewechselt - BSG kann ein Captcha verlangen. Wieder sicher in {remaining}.",
+		CooldownConfirm: "{name} wurde in den letzten {minutes} Minuten {count}x gewechselt - BSG kann ein Captcha verlangen. Wieder sicher in {remaining}. Trotzdem wechseln?",
+		StatusCooldown:  "Sicher in {time}",
+		DurationMinutes: "{minutes} Min.",
+		WarnCefNotSaved: "Launcher-Profil (CEF) nicht gesichert: {error}",
+
+		// Forwarded actions
+		NotifyTitle:        "Tarkov Account Switcher",
+		NotifySwitched:     "{name}: {message}",
+		NotifySwitchFailed: "Wechsel fehlgeschlagen: {error}",
+
+		// Desktop notifications
+		NotifyCaptured:       "Session für {name} gespeichert",
+		NotifyCaptureTimeout: "Kein Login für {name} erkannt - Session nicht gespeichert",
+		NotifyGameNotStarted: "Spiel für {name} nicht gestartet: {error}",
+		NotifyExpiring:       "Die Session von {name} ist {days} Tage alt - einmal wechseln, um sie zu erneuern",
+		LabelNotifications:   "Benachrichtigungen",
+		NotifyOptCaptured:    "Session gespeichert",
+		NotifyOptTimeout:     "Kein Login erkannt",
+		NotifyOptFailed:      "Wechsel fehlgeschlagen",
+		NotifyOptExpiring:    "Session läuft bald ab",
+
+		// Shortcuts and links
+		BtnShortcut:           "Verknüpfung",
+		StatusShortcutCreated: "Verknüpfung erstellt: {path}",
+		LabelURIScheme:        "tarkovswitch://-Links registrieren",
+		URISchemeHelp:         "Für Stream Deck und Links: tarkovswitch://switch/<id>",
+
+		// Local HTTP API
+		LabelAPI:          "Lokale HTTP-API",
+		APIHelp:           "http://127.0.0.1:{port} - nur mit Bearer-Token",
+		BtnCopyToken:      "Token kopieren",
+		StatusTokenCopied: "API-Token kopiert",
+		APIStartFailed:    "API läuft nicht: {error}",
+
 		// Tray Menu
-		TrayOpen: "Öffnen",
-		TrayQuit: "Beenden",
+		TrayOpen:       "Öffnen",
+		TrayQuit:       "Beenden",
+		TrayNoSession:  "{name} (keine Session)",
+		TrayNoAccounts: "Keine Accounts",
+
+		// Tray Tooltip
+		TipLoggedIn:  "Angemeldet: {name}",
+		TipLoggedOut: "Nicht angemeldet",
+		TipWatching:  "Warte auf Login: {name}",
+		TipFailed:    "Letzter Wechsel fehlgeschlagen",
+		TipUpdate:    "Update verfügbar: {version}",
+
+		// Diagnostics
+		LabelDiagnostics:       "Diagnose",
+		DiagnosticsHelp:        "Prüft Launcher, Einstellungen, Schlüssel und Sessions. Das Support-Paket enthält den Bericht und die Logs ohne Tokens und E-Mails.",
+		BtnRunDiagnostics:      "Diagnose starten",
+		BtnSupportBundle:       "Support-Paket speichern",
+		StatusBundleSaved:      "Support-Paket gespeichert: {path}",
+		DoctorDataDir:          "Datenordner beschreibbar",
+		DoctorSettings:         "Einstellungen lesbar",
+		DoctorWine:             "Wine-Prefix",
+		DoctorLauncherPath:     "Launcher-Pfad",
+		DoctorLauncherSettings: "Launcher-Einstellungen",
+		DoctorLauncherWritable: "Launcher-Einstellungen beschreibbar",
+		DoctorLauncherRunning:  "Launcher läuft",
+		DoctorGameIni:          "Game.ini",
+		DoctorKey:              "Schlüsseldatei",
+		DoctorAccounts:         "Accounts lesbar",
+		DoctorSessions:         "Sessions entschlüsselbar",
+
+		// Errors
+		ErrAccountNotFound:      "Account nicht gefunden",
+		ErrAccountsUnreadable:   "Accounts konnten nicht geladen werden: {error}",
+		ErrAccountsUnwritable:   "Accounts konnten nicht gespeichert werden: {error}",
+		ErrUnknownGame:          "Unbekanntes Spiel: {game}",
+		ErrGameRunning:          "Das Spiel läuft noch - bitte vor dem Wechsel beenden",
+		ErrLauncherNotFound:     "BSG Launcher nicht gefunden: {path} - bitte den Launcher-Pfad in den Einstellungen prüfen",
+		ErrLauncherStartFailed:  "BSG Launcher konnte nicht gestartet werden: {error}",
+		ErrKillTimeout:          "Der BSG Launcher hat sich nicht rechtzeitig beendet - bitte manuell schließen und erneut versuchen",
+		ErrSettingsUnreadable:   "Launcher-Einstellungen konnten nicht gelesen werden: {error}",
+		ErrSettingsUnwritable:   "Launcher-Einstellungen konnten nicht geschrieben werden: {error}",
+		ErrSessionUndecryptable: "Die gespeicherte Session kann nicht entschlüsselt werden - bitte neu einloggen",
+		ErrNotLoggedIn:          "Der Launcher ist nicht eingeloggt",
+		ErrNoAccountForLogin:    "Kein gespeicherter Account für {email}",
+		ErrHookVeto:             "Wechsel durch Hook abgebrochen: {error}",
+		ErrProfileFailed:        "Account-Profil konnte nicht übernommen werden: {error}",
+		ErrBusy:                 "Ein Wechsel oder Login läuft bereits - bitte warten",
+		ErrCancelled:            "Vorgang abgebrochen",
+		ErrTimedOut:             "Zeitüberschreitung - Vorgang abgebrochen",
+		ErrAccountAmbiguous:     "\"{query}\" passt auf mehrere Accounts: {names}",
+		ErrNoAccountGiven:       "Kein Account angegeben",
+		ErrImportVersion:        "Export-Version {version} wird nicht unterstützt",
+		ErrImportInvalid:        "Die Export-Datei enthält einen Account ohne Name oder E-Mail",
+		ErrInvalidArguments:     "Ungültige Argumente: {arg} fehlt oder passt nicht",
+		ErrInvalidLink:          "Ungültiger tarkovswitch://-Link",
+		ErrInvalidCacheTarget:   "Cache-Ziel {target} übersprungen: es muss innerhalb von %TEMP%, %LOCALAPPDATA% oder %LAUNCHER_DIR% liegen",
+		ErrNoLaunchArgs:         "Keine Launcher-Argumente für {game} in gameLaunchArgs eingetragen",
+		ErrGameNotStarted:       "{game} wurde nach dem Login nicht gestartet",
+
+		// Switch steps
+		StepSaveSession:     "Aktuelle Session sichern",
+		StepKillLauncher:    "Launcher beenden",
+		StepClearCache:      "Cache leeren",
+		StepRestoreProfiles: "Profile wiederherstellen",
+		StepRestoreSession:  "Session wiederherstellen",
+		StepResetSession:    "Session zurücksetzen",
+		StepStartLauncher:   "Launcher starten",
+		StepTotal:           "Gesamt",
 
 		// Status Messages
 		StatusFillFields:     "Bitte fülle alle Felder aus",
@@ -184,6 +400,7 @@ var translations = map[string]map[string]string{
 		LabelStreamerMode:   "Streamer Mode",
 		StreamerModeHelp:    "Hides email addresses with ****",
 		LabelTheme:          "Theme / Design",
+		LabelDataDir:        "Data folder",
 		LabelAutoStart:      "Start with Windows",
 		AutoStartHelp:       "Automatically start the app on Windows login",
 		BtnQuit:             "Quit",
@@ -192,9 +409,115 @@ var translations = map[string]map[string]string{
 		SwitchAutoLogin:  "Launcher started - Auto-login active!",
 		SwitchManualLogin: "Please login - session will be saved automatically!",
 
+		// Post-switch verification
+		VerifyLoggedIn: "Logged in as {name}",
+		VerifyRejected: "Session rejected - please log in again, the session will be saved automatically!",
+
+		// Switch cooldown
+		CooldownWarning: "{name} was switched to {count} times in the last {minutes} minutes - BSG may ask for a captcha. Safe again in {remaining}.",
+		CooldownConfirm: "{name} was switched to {count} times in the last {minutes} minutes - BSG may ask for a captcha. Safe again in {remaining}. Switch anyway?",
+		StatusCooldown:  "Safe in {time}",
+		DurationMinutes: "{minutes} min",
+		WarnCefNotSaved: "Launcher (CEF) profile not saved: {error}",
+
+		// Forwarded actions
+		NotifyTitle:        "Tarkov Account Switcher",
+		NotifySwitched:     "{name}: {message}",
+		NotifySwitchFailed: "Switch failed: {error}",
+
+		// Desktop notifications
+		NotifyCaptured:       "Session saved for {name}",
+		NotifyCaptureTimeout: "No login detected for {name} - session not saved",
+		NotifyGameNotStarted: "Game not started for {name}: {error}",
+		NotifyExpiring:       "The session of {name} is {days} days old - switch to it once to refresh it",
+		LabelNotifications:   "Notifications",
+		NotifyOptCaptured:    "Session saved",
+		NotifyOptTimeout:     "No login detected",
+		NotifyOptFailed:      "Switch failed",
+		NotifyOptExpiring:    "Session expiring",
+
+		// Shortcuts and links
+		BtnShortcut:           "Shortcut",
+		StatusShortcutCreated: "Shortcut created: {path}",
+		LabelURIScheme:        "Register tarkovswitch:// links",
+		URISchemeHelp:         "For Stream Deck and links: tarkovswitch://switch/<id>",
+
+		// Local HTTP API
+		LabelAPI:          "Local HTTP API",
+		APIHelp:           "http://127.0.0.1:{port} - bearer token required",
+		BtnCopyToken:      "Copy token",
+		StatusTokenCopied: "API token copied",
+		APIStartFailed:    "API is not running: {error}",
+
 		// Tray Menu
-		TrayOpen: "Open",
-		TrayQuit: "Quit",
+		TrayOpen:       "Open",
+		TrayQuit:       "Quit",
+		TrayNoSession:  "{name} (no session)",
+		TrayNoAccounts: "No accounts",
+
+		// Tray Tooltip
+		TipLoggedIn:  "Logged in: {name}",
+		TipLoggedOut: "Not logged in",
+		TipWatching:  "Waiting for login: {name}",
+		TipFailed:    "Last switch failed",
+		TipUpdate:    "Update available: {version}",
+
+		// Diagnostics
+		LabelDiagnostics:       "Diagnostics",
+		DiagnosticsHelp:        "Checks the launcher, settings, key and sessions. The support bundle contains the report and the logs without tokens or emails.",
+		BtnRunDiagnostics:      "Run diagnostics",
+		BtnSupportBundle:       "Save support bundle",
+		StatusBundleSaved:      "Support bundle saved: {path}",
+		DoctorDataDir:          "Data directory writable",
+		DoctorSettings:         "Settings readable",
+		DoctorWine:             "Wine prefix",
+		DoctorLauncherPath:     "Launcher path",
+		DoctorLauncherSettings: "Launcher settings",
+		DoctorLauncherWritable: "Launcher settings writable",
+		DoctorLauncherRunning:  "Launcher running",
+		DoctorGameIni:          "Game.ini",
+		DoctorKey:              "Key file",
+		DoctorAccounts:         "Accounts readable",
+		DoctorSessions:         "Sessions decryptable",
+
+		// Errors
+		ErrAccountNotFound:      "Account not found",
+		ErrAccountsUnreadable:   "Accounts could not be loaded: {error}",
+		ErrAccountsUnwritable:   "Accounts could not be saved: {error}",
+		ErrUnknownGame:          "Unknown game: {game}",
+		ErrGameRunning:          "The game is still running - close it before switching",
+		ErrLauncherNotFound:     "BSG Launcher not found: {path} - check the launcher path in the settings",
+		ErrLauncherStartFailed:  "BSG Launcher could not be started: {error}",
+		ErrKillTimeout:          "The BSG Launcher did not close in time - close it manually and try again",
+		ErrSettingsUnreadable:   "Launcher settings could not be read: {error}",
+		ErrSettingsUnwritable:   "Launcher settings could not be written: {error}",
+		ErrSessionUndecryptable: "The saved session cannot be decrypted - please log in again",
+		ErrNotLoggedIn:          "The launcher is not logged in",
+		ErrNoAccountForLogin:    "No saved account for {email}",
+		ErrHookVeto:             "Switch cancelled by hook: {error}",
+		ErrProfileFailed:        "Account profile could not be applied: {error}",
+		ErrBusy:                 "A switch or login is already running - please wait",
+		ErrCancelled:            "Operation cancelled",
+		ErrTimedOut:             "Operation timed out and was cancelled",
+		ErrAccountAmbiguous:     "\"{query}\" matches several accounts: {names}",
+		ErrNoAccountGiven:       "No account given",
+		ErrImportVersion:        "Export version {version} is not supported",
+		ErrImportInvalid:        "The export file has an account without name or email",
+		ErrInvalidArguments:     "Invalid arguments: {arg} is missing or does not fit",
+		ErrInvalidLink:          "Invalid tarkovswitch:// link",
+		ErrInvalidCacheTarget:   "Cache target {target} skipped: it must lie inside %TEMP%, %LOCALAPPDATA% or %LAUNCHER_DIR%",
+		ErrNoLaunchArgs:         "No launcher arguments for {game} in gameLaunchArgs",
+		ErrGameNotStarted:       "{game} did not start after the login",
+
+		// Switch steps
+		StepSaveSession:     "Save current session",
+		StepKillLauncher:    "Close launcher",
+		StepClearCache:      "Clear cache",
+		StepRestoreProfiles: "Restore profiles",
+		StepRestoreSession:  "Restore session",
+		StepResetSession:    "Reset session",
+		StepStartLauncher:   "Start launcher",
+		StepTotal:           "Total",
 
 		// Status Messages
 		StatusFillFields:     "Please fill all fields",
@@ -259,7 +582,22 @@ func T(key string) string {
 
 // TF returns the translation with placeholders replaced
 func TF(key string, replacements map[string]string) string {
-	text := T(key)
+	return replace(T(key), replacements)
+}
+
+// TFLang is TF in a fixed language, e.g. English for log messages
+func TFLang(lang, key string, replacements map[string]string) string {
+	text, ok := translations[lang][key]
+	if !ok {
+		text, ok = translations["en"][key]
+	}
+	if !ok {
+		text = key
+	}
+	return replace(text, replacements)
+}
+
+func replace(text string, replacements map[string]string) string {
 	for placeholder, value := range replacements {
 		text = strings.ReplaceAll(text, "{"+placeholder+"}", value)
 	}
@@ -0,0 +1,51 @@
+package launcher
+
+import (
+	"bytes"
+	"os/exec"
+	"strings"
+
+	"tarkov-account-switcher/internal/config"
+)
+
+// launcherExe is the image name of the BSG launcher process
+const launcherExe = "BsgLauncher.exe"
+
+// gameExes are the image names of the game processes (EFT, Arena)
+var gameExes = []string{"EscapeFromTarkov.exe", "EscapeFromTarkovArena.exe"}
+
+// backend starts, detects and stops the launcher process.
+// Everything else (settings, sessions, caches) works on plain files and is
+// shared between backends.
+type backend interface {
+	start(launcherPath string, args ...string) error
+	kill() error
+	isRunning(image string) bool
+}
+
+// currentBackend returns the backend for this platform
+func currentBackend() backend {
+	if config.IsWine() {
+		return wineBackend{}
+	}
+	return windowsBackend{}
+}
+
+// windowsBackend runs the launcher natively and manages it via taskkill/tasklist
+type windowsBackend struct{}
+
+func (windowsBackend) start(launcherPath string, args ...string) error {
+	cmd := exec.Command(launcherPath, args...)
+	return cmd.Start()
+}
+
+func (windowsBackend) kill() error {
+	cmd := exec.Command("taskkill", "/F", "/IM", launcherExe, "/T")
+	return cmd.Run()
+}
+
+func (windowsBackend) isRunning(image string) bool {
+	check := exec.Command("tasklist", "/FI", "IMAGENAME eq "+image, "/NH")
+	out, _ := check.Output()
+	return bytes.Contains(bytes.ToLower(out), []byte(strings.ToLower(image)))
+}
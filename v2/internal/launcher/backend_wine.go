@@ -0,0 +1,85 @@
+package launcher
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"tarkov-account-switcher/internal/config"
+)
+
+// wineBackend runs the launcher through a configurable wine/umu command line
+// inside the configured prefix, and finds it via /proc
+type wineBackend struct{}
+
+func (wineBackend) start(launcherPath string, args ...string) error {
+	wine := config.GetSettings().Wine
+	if len(wine.Command) == 0 {
+		return errors.New("no wine command configured")
+	}
+
+	cmdArgs := append(append(append([]string{}, wine.Command[1:]...), launcherPath), args...)
+	cmd := exec.Command(wine.Command[0], cmdArgs...)
+
+	cmd.Env = append(os.Environ(), "WINEPREFIX="+config.WinePrefix())
+	for key, val := range wine.Env {
+		cmd.Env = append(cmd.Env, key+"="+val)
+	}
+
+	return cmd.Start()
+}
+
+func (wineBackend) kill() error {
+	var firstErr error
+	for _, pid := range findPIDs(launcherExe) {
+		proc, err := os.FindProcess(pid)
+		if err == nil {
+			err = proc.Kill()
+		}
+		if err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+func (wineBackend) isRunning(image string) bool {
+	return len(findPIDs(image)) > 0
+}
+
+// findPIDs scans /proc for processes running the given Windows image.
+// Under Wine the Windows image path shows up in the process command line.
+func findPIDs(image string) []int {
+	entries, err := os.ReadDir("/proc")
+	if err != nil {
+		return nil
+	}
+
+	self := os.Getpid()
+	var pids []int
+	for _, e := range entries {
+		pid, err := strconv.Atoi(e.Name())
+		if err != nil || pid == self {
+			continue
+		}
+
+		cmdline, err := os.ReadFile(filepath.Join("/proc", e.Name(), "cmdline"))
+		if err != nil || len(cmdline) == 0 {
+			continue
+		}
+
+		// Only look at the executable (first argument), not at wine/umu
+		// wrappers that merely pass the launcher path along
+		exe := string(bytes.SplitN(cmdline, []byte{0}, 2)[0])
+		exe = path.Base(strings.ReplaceAll(exe, `\`, "/"))
+		if strings.EqualFold(exe, image) {
+			pids = append(pids, pid)
+		}
+	}
+	return pids
+}
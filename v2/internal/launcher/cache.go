@@ -0,0 +1,178 @@
+package launcher
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"tarkov-account-switcher/internal/apperror"
+	"tarkov-account-switcher/internal/config"
+)
+
+// CacheEntry is the result for a single cache target
+type CacheEntry struct {
+	Target  string // configured target, before expansion
+	Path    string // expanded absolute path
+	Exists  bool
+	Size    int64 // bytes found (or that would be freed in a dry run)
+	Removed bool
+	Error   string
+}
+
+// CacheReport is the result of clearing (or previewing) a set of cache targets
+type CacheReport struct {
+	DryRun    bool
+	Entries   []CacheEntry
+	TotalSize int64
+}
+
+// ExpandCacheTarget resolves the placeholder a cache target starts with.
+// Both / and \ are accepted as separators. The result must lie strictly
+// below that placeholder's directory, so neither the directory itself nor
+// anything outside it (e.g. via "..") can be cleared.
+func ExpandCacheTarget(target string) (string, error) {
+	settings := config.GetSettings()
+	dirs := config.GetGameDirs()
+
+	vars := map[string]string{
+		"%TEMP%":         dirs.Temp,
+		"%LOCALAPPDATA%": dirs.LocalAppData,
+		"%LAUNCHER_DIR%": filepath.Dir(config.HostPath(settings.LauncherPath)),
+	}
+
+	var base, rest string
+	for name, value := range vars {
+		if strings.HasPrefix(target, name) {
+			if value == "" || value == "." {
+				return "", apperror.New(apperror.InvalidCacheTarget, errors.New(name+" is not set"), "target", target)
+			}
+			base, rest = cleanPath(value), target[len(name):]
+			break
+		}
+	}
+	if base == "" {
+		return "", apperror.New(apperror.InvalidCacheTarget, nil, "target", target)
+	}
+	if strings.Contains(rest, "%") {
+		return "", apperror.New(apperror.InvalidCacheTarget, errors.New("unknown placeholder"), "target", target)
+	}
+
+	// Never RemoveAll a relative path, a drive/filesystem root or a whole
+	// base directory like LocalAppData
+	expanded := cleanPath(base + "/" + rest)
+	if !filepath.IsAbs(expanded) || filepath.Dir(expanded) == expanded || !isBelow(expanded, base) {
+		return "", apperror.New(apperror.InvalidCacheTarget, errors.New("refusing to clear "+expanded), "target", target)
+	}
+	return expanded, nil
+}
+
+// cleanPath accepts both separators and cleans the result
+func cleanPath(path string) string {
+	return filepath.Clean(filepath.FromSlash(strings.ReplaceAll(path, `\`, "/")))
+}
+
+// isBelow reports whether path lies strictly inside dir
+func isBelow(path, dir string) bool {
+	rel, err := filepath.Rel(dir, path)
+	if err != nil || filepath.IsAbs(rel) {
+		return false
+	}
+	return rel != "." && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
+// ClearGameCache clears the given cache targets to force fresh data from the server.
+// With dryRun set nothing is deleted; the report shows what would be removed.
+// This does NOT delete our saved account sessions - only game cache
+func ClearGameCache(targets []string, dryRun bool) CacheReport {
+	report := CacheReport{DryRun: dryRun}
+
+	for _, target := range targets {
+		entry := CacheEntry{Target: target}
+
+		path, err := ExpandCacheTarget(target)
+		if err != nil {
+			entry.Error = apperror.Message(err)
+			report.Entries = append(report.Entries, entry)
+			continue
+		}
+		entry.Path = path
+
+		clearPath(&entry, dryRun)
+		report.TotalSize += entry.Size
+		report.Entries = append(report.Entries, entry)
+	}
+
+	return report
+}
+
+// CleanTempFolder empties our own temp folder (the launcher's tempFolder).
+// Runs independently of switches, e.g. on startup.
+func CleanTempFolder(dryRun bool) CacheReport {
+	paths := config.GetPaths()
+	report := CacheReport{DryRun: dryRun}
+
+	entries, err := os.ReadDir(paths.TempFolder)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			report.Entries = append(report.Entries, CacheEntry{
+				Target: paths.TempFolder,
+				Path:   paths.TempFolder,
+				Error:  err.Error(),
+			})
+		}
+		return report
+	}
+
+	// Remove the contents, not the folder itself - the launcher expects it to exist
+	for _, e := range entries {
+		path := filepath.Join(paths.TempFolder, e.Name())
+		entry := CacheEntry{Target: path, Path: path}
+		clearPath(&entry, dryRun)
+		report.TotalSize += entry.Size
+		report.Entries = append(report.Entries, entry)
+	}
+
+	return report
+}
+
+// clearPath measures and (unless dryRun) removes entry.Path
+func clearPath(entry *CacheEntry, dryRun bool) {
+	if _, err := os.Lstat(entry.Path); err != nil {
+		if !os.IsNotExist(err) {
+			entry.Error = err.Error()
+		}
+		return
+	}
+	entry.Exists = true
+	entry.Size = dirSize(entry.Path)
+
+	if dryRun {
+		return
+	}
+
+	if err := os.RemoveAll(entry.Path); err != nil {
+		entry.Error = err.Error()
+		return
+	}
+	entry.Removed = true
+}
+
+// dirSize returns the total size of all regular files below path.
+// Unreadable entries are skipped.
+func dirSize(path string) int64 {
+	var size int64
+	filepath.WalkDir(path, func(_ string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		if d.Type().IsRegular() {
+			if info, err := d.Info(); err == nil {
+				size += info.Size()
+			}
+		}
+		return nil
+	})
+	return size
+}
@@ -0,0 +1,99 @@
+package launcher
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"tarkov-account-switcher/internal/apperror"
+	"tarkov-account-switcher/internal/config"
+)
+
+// TestMain keeps the tests away from the real data directory
+func TestMain(m *testing.M) {
+	dir, err := os.MkdirTemp("", "tarkov-switcher-test-")
+	if err != nil {
+		panic(err)
+	}
+	os.Setenv(config.DataDirEnv, dir)
+
+	code := m.Run()
+	os.RemoveAll(dir)
+	os.Exit(code)
+}
+
+// useGameDirs points the launcher folders (Windows and Wine) into a temp dir
+// and returns them with the launcher directory
+func useGameDirs(t *testing.T, launcherPath string) (config.GameDirs, string) {
+	t.Helper()
+	dir := t.TempDir()
+	t.Setenv("LOCALAPPDATA", filepath.Join(dir, "Local"))
+	t.Setenv("TMP", filepath.Join(dir, "Temp"))
+	t.Setenv("TEMP", filepath.Join(dir, "Temp"))
+
+	settings := config.GetSettings()
+	wine, launcher := settings.Wine, settings.LauncherPath
+	settings.Wine.Prefix, settings.Wine.User = dir, "test"
+	settings.LauncherPath = launcherPath
+	if launcherPath != "" {
+		settings.LauncherPath = filepath.Join(dir, launcherPath)
+	}
+	t.Cleanup(func() { settings.Wine, settings.LauncherPath = wine, launcher })
+
+	return config.GetGameDirs(), filepath.Dir(settings.LauncherPath)
+}
+
+func TestExpandCacheTarget(t *testing.T) {
+	tests := []struct {
+		name     string
+		target   string
+		launcher string // launcher path below the temp dir, "" for unset
+		want     func(dirs config.GameDirs, launcherDir string) string
+	}{
+		{"temp", `%TEMP%\Battlestate Games\EscapeFromTarkov`, "", func(d config.GameDirs, _ string) string {
+			return filepath.Join(d.Temp, "Battlestate Games", "EscapeFromTarkov")
+		}},
+		{"slashes", `%LOCALAPPDATA%/Battlestate Games/BsgLauncher/CefCache/Cache`, "", func(d config.GameDirs, _ string) string {
+			return filepath.Join(d.LocalAppData, "Battlestate Games", "BsgLauncher", "CefCache", "Cache")
+		}},
+		{"launcher dir", `%LAUNCHER_DIR%\Temp`, filepath.Join("Launcher", "BsgLauncher.exe"), func(_ config.GameDirs, l string) string {
+			return filepath.Join(l, "Temp")
+		}},
+		{"inner dots stay inside", `%TEMP%\a\..\b`, "", func(d config.GameDirs, _ string) string {
+			return filepath.Join(d.Temp, "b")
+		}},
+		{"placeholder alone", `%TEMP%`, "", nil},
+		{"trailing separator", `%LOCALAPPDATA%\`, "", nil},
+		{"dot", `%TEMP%\.`, "", nil},
+		{"parent", `%TEMP%\..`, "", nil},
+		{"escape to a sibling", `%TEMP%\..\Local\Battlestate Games`, "", nil},
+		{"escape deeper", `%LOCALAPPDATA%\a\..\..\..\..`, "", nil},
+		{"no placeholder", `C:\Windows\Temp`, "", nil},
+		{"placeholder not at the start", `x%TEMP%\a`, "", nil},
+		{"unknown placeholder", `%TEMP%\%APPDATA%`, "", nil},
+		{"launcher dir unset", `%LAUNCHER_DIR%\Temp`, "", nil},
+		{"empty", ``, "", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dirs, launcherDir := useGameDirs(t, tt.launcher)
+
+			got, err := ExpandCacheTarget(tt.target)
+			if tt.want == nil {
+				if err == nil {
+					t.Fatalf("ExpandCacheTarget(%q) = %q, want an error", tt.target, got)
+				}
+				if code := apperror.CodeOf(err); code != apperror.InvalidCacheTarget {
+					t.Errorf("code = %q, want %q", code, apperror.InvalidCacheTarget)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ExpandCacheTarget(%q) = %v", tt.target, err)
+			}
+			if want := tt.want(dirs, launcherDir); got != want {
+				t.Errorf("ExpandCacheTarget(%q) = %q, want %q", tt.target, got, want)
+			}
+		})
+	}
+}
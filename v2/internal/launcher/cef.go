@@ -0,0 +1,176 @@
+package launcher
+
+import (
+	"archive/zip"
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"tarkov-account-switcher/internal/config"
+)
+
+// DefaultCefProfileFilter selects the launcher's cookie and local storage
+// files. Caches (Cache, Code Cache, GPUCache) are deliberately left out.
+var DefaultCefProfileFilter = ProfileFilter{
+	Include: []string{
+		"Cookies",
+		"Cookies-journal",
+		"Network/Cookies",
+		"Network/Cookies-journal",
+		"Local Storage/leveldb/*",
+		"Session Storage/*",
+	},
+	Exclude: []string{"LOCK"},
+}
+
+// GetCefProfileDir returns the launcher's CEF profile directory
+func GetCefProfileDir() string {
+	return filepath.Join(config.GetGameDirs().LocalAppData, "Battlestate Games", "BsgLauncher", "CefCache")
+}
+
+// CollectCefProfile packs the matching CEF profile files into a zip archive.
+// The launcher must not be running, otherwise the cookie database may be
+// mid-write. Fails if the files add up to more than maxBytes.
+func CollectCefProfile(filter ProfileFilter, maxBytes int64) ([]byte, error) {
+	dir := GetCefProfileDir()
+	files, err := listProfileFiles(dir, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	var total int64
+	for _, rel := range files {
+		info, err := os.Stat(filepath.Join(dir, rel))
+		if err != nil {
+			return nil, err
+		}
+		total += info.Size()
+	}
+	if maxBytes > 0 && total > maxBytes {
+		return nil, fmt.Errorf("CEF profile is %d bytes, limit is %d", total, maxBytes)
+	}
+
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for _, rel := range files {
+		data, err := os.ReadFile(filepath.Join(dir, rel))
+		if err != nil {
+			return nil, err
+		}
+		w, err := zw.Create(rel)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := w.Write(data); err != nil {
+			return nil, err
+		}
+	}
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// CefProfileFiles returns every live file a RestoreCefProfile with the given
+// archive would write or delete, so they can be snapshotted beforehand
+func CefProfileFiles(archive []byte, filter ProfileFilter) ([]string, error) {
+	dir := GetCefProfileDir()
+
+	entries, err := readCefArchive(archive, filter, 0)
+	if err != nil {
+		return nil, err
+	}
+	existing, err := listProfileFiles(dir, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[string]bool)
+	var files []string
+	for _, rel := range existing {
+		seen[rel] = true
+		files = append(files, filepath.Join(dir, rel))
+	}
+	for rel := range entries {
+		if !seen[rel] {
+			files = append(files, filepath.Join(dir, rel))
+		}
+	}
+	return files, nil
+}
+
+// RestoreCefProfile replaces the matching CEF profile files with the archive
+// contents. Matching files that are not in the archive are removed, so no
+// cookies of the previous account survive the switch.
+func RestoreCefProfile(archive []byte, filter ProfileFilter, maxBytes int64) error {
+	dir := GetCefProfileDir()
+
+	entries, err := readCefArchive(archive, filter, maxBytes)
+	if err != nil {
+		return err
+	}
+
+	existing, err := listProfileFiles(dir, filter)
+	if err != nil {
+		return err
+	}
+	for _, rel := range existing {
+		if _, ok := entries[rel]; !ok {
+			if err := os.Remove(filepath.Join(dir, rel)); err != nil && !os.IsNotExist(err) {
+				return err
+			}
+		}
+	}
+
+	for rel, data := range entries {
+		dst := filepath.Join(dir, rel)
+		if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+			return err
+		}
+		if err := os.WriteFile(dst, data, 0644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// readCefArchive unpacks a profile archive. Entries outside the filter or
+// with unsafe paths are rejected, and the unpacked size is capped at maxBytes.
+func readCefArchive(archive []byte, filter ProfileFilter, maxBytes int64) (map[string][]byte, error) {
+	zr, err := zip.NewReader(bytes.NewReader(archive), int64(len(archive)))
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make(map[string][]byte, len(zr.File))
+	var total int64
+	for _, f := range zr.File {
+		if !filepath.IsLocal(filepath.FromSlash(f.Name)) || !filter.Match(f.Name) {
+			return nil, fmt.Errorf("unexpected entry in CEF profile: %q", f.Name)
+		}
+
+		rc, err := f.Open()
+		if err != nil {
+			return nil, err
+		}
+		var r io.Reader = rc
+		if maxBytes > 0 {
+			r = io.LimitReader(rc, maxBytes-total+1)
+		}
+		data, err := io.ReadAll(r)
+		rc.Close()
+		if err != nil {
+			return nil, err
+		}
+
+		total += int64(len(data))
+		if maxBytes > 0 && total > maxBytes {
+			return nil, fmt.Errorf("CEF profile exceeds limit of %d bytes", maxBytes)
+		}
+		entries[f.Name] = data
+	}
+	return entries, nil
+}
@@ -1,32 +1,57 @@
 package launcher
 
 import (
-	"bytes"
+	"context"
+	"log/slog"
 	"os"
-	"os/exec"
-	"path/filepath"
 	"time"
 
+	"tarkov-account-switcher/internal/apperror"
 	"tarkov-account-switcher/internal/config"
 )
 
-// KillLauncher kills the BSG Launcher process and waits for it to exit
-func KillLauncher() error {
-	cmd := exec.Command("taskkill", "/F", "/IM", "BsgLauncher.exe", "/T")
-	cmd.Run() // Ignore error - process might not be running
+// KillLauncher kills the BSG Launcher process and waits for it to exit.
+// Fails with apperror.KillTimeout if it is still running after 3 seconds,
+// or with the context's error if ctx ends first.
+func KillLauncher(ctx context.Context) error {
+	backend := currentBackend()
+	if err := backend.kill(); err != nil {
+		// Also fails when the launcher was not running
+		slog.Debug("launcher kill", "err", err)
+	}
 
 	// Poll for process exit instead of fixed sleep
 	deadline := time.Now().Add(3 * time.Second)
 	for time.Now().Before(deadline) {
-		check := exec.Command("tasklist", "/FI", "IMAGENAME eq BsgLauncher.exe", "/NH")
-		out, _ := check.Output()
-		if !bytes.Contains(out, []byte("BsgLauncher")) {
+		if !backend.isRunning(launcherExe) {
+			slog.Info("launcher stopped")
 			return nil
 		}
-		time.Sleep(100 * time.Millisecond)
+		select {
+		case <-ctx.Done():
+			return apperror.FromContext(ctx.Err())
+		case <-time.After(100 * time.Millisecond):
+		}
 	}
 
-	return nil
+	slog.Warn("launcher still running after kill")
+	return apperror.New(apperror.KillTimeout, nil)
+}
+
+// IsLauncherRunning checks whether a BsgLauncher process exists
+func IsLauncherRunning() bool {
+	return currentBackend().isRunning(launcherExe)
+}
+
+// IsGameRunning checks whether EFT or Arena is running
+func IsGameRunning() bool {
+	backend := currentBackend()
+	for _, image := range gameExes {
+		if backend.isRunning(image) {
+			return true
+		}
+	}
+	return false
 }
 
 // StartLauncher starts the BSG Launcher
@@ -34,34 +59,48 @@ func StartLauncher() error {
 	settings := config.GetSettings()
 	launcherPath := settings.LauncherPath
 
-	if _, err := os.Stat(launcherPath); os.IsNotExist(err) {
-		return err
+	if _, err := os.Stat(config.HostPath(launcherPath)); launcherPath == "" || os.IsNotExist(err) {
+		return apperror.New(apperror.LauncherNotFound, err, "path", launcherPath)
 	}
 
-	cmd := exec.Command(launcherPath)
-	return cmd.Start()
+	if err := currentBackend().start(launcherPath); err != nil {
+		return apperror.New(apperror.LauncherStartFailed, err)
+	}
+	slog.Info("launcher started", "path", launcherPath)
+	return nil
 }
 
-// OnLauncherStarted is called after launcher starts - set by UI to minimize window
-var OnLauncherStarted func()
+// Games selectable in the launcher (values of the selectedGame setting)
+const (
+	GameEFT   = "eft"
+	GameArena = "arena"
+)
 
-// ClearGameCache clears EFT/Arena cache files to force fresh data from server
-// This does NOT delete our saved account sessions - only game cache
-func ClearGameCache() {
-	tempDir := os.TempDir()
-	localAppData := os.Getenv("LOCALAPPDATA")
+// IsValidGame reports whether game is a known selectedGame value
+func IsValidGame(game string) bool {
+	return game == GameEFT || game == GameArena
+}
 
-	settings := config.GetSettings()
-	launcherDir := filepath.Dir(settings.LauncherPath)
+// StartGame asks the running launcher to start the given game.
+// The launcher is single-instance, so starting it again with the configured
+// play arguments hands them over to the already logged-in instance.
+// Fails with apperror.NoLaunchArgs unless gameLaunchArgs has an entry for game.
+func StartGame(game string) error {
+	if !IsValidGame(game) {
+		return apperror.New(apperror.UnknownGame, nil, "game", game)
+	}
 
-	cacheDirs := []string{
-		filepath.Join(tempDir, "Battlestate Games", "EscapeFromTarkov"),
-		filepath.Join(tempDir, "Battlestate Games", "EscapeFromTarkovArena"),
-		filepath.Join(localAppData, "Battlestate Games", "BsgLauncher", "CefCache", "Cache"),
-		filepath.Join(launcherDir, "Temp"),
+	settings := config.GetSettings()
+	args, ok := settings.GameLaunchArgs[game]
+	if !ok || len(args) == 0 {
+		return apperror.New(apperror.NoLaunchArgs, nil, "game", game)
 	}
 
-	for _, dir := range cacheDirs {
-		os.RemoveAll(dir)
+	if err := currentBackend().start(settings.LauncherPath, args...); err != nil {
+		return apperror.New(apperror.LauncherStartFailed, err)
 	}
+	return nil
 }
+
+// OnLauncherStarted is called after launcher starts - set by UI to minimize window
+var OnLauncherStarted func()
@@ -0,0 +1,154 @@
+package launcher
+
+import (
+	"bytes"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// ProfileFilter selects which files of the EFT Settings directory belong to a profile.
+// Patterns use filepath.Match syntax against the slash-separated path relative to
+// the Settings directory; a pattern without a slash also matches the file name.
+type ProfileFilter struct {
+	Include []string
+	Exclude []string
+}
+
+// Match reports whether the relative path rel is part of the profile
+func (f ProfileFilter) Match(rel string) bool {
+	return matchAny(f.Include, rel) && !matchAny(f.Exclude, rel)
+}
+
+func matchAny(patterns []string, rel string) bool {
+	for _, pattern := range patterns {
+		if ok, _ := path.Match(pattern, rel); ok {
+			return true
+		}
+		if !strings.Contains(pattern, "/") {
+			if ok, _ := path.Match(pattern, path.Base(rel)); ok {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// GetGameSettingsDir returns the EFT Settings directory that holds Game.ini
+func GetGameSettingsDir() string {
+	return filepath.Dir(GetGameSettingsPath())
+}
+
+// SaveSettingsProfile copies the matching files of the EFT Settings directory
+// into profileDir, replacing the previous profile. The new copy is built next
+// to the old one first, so a failed save never loses the existing profile.
+func SaveSettingsProfile(profileDir string, filter ProfileFilter) error {
+	files, err := listProfileFiles(GetGameSettingsDir(), filter)
+	if err != nil {
+		return err
+	}
+
+	tmpDir := profileDir + ".tmp"
+	os.RemoveAll(tmpDir)
+
+	for _, rel := range files {
+		if err := copyFile(filepath.Join(GetGameSettingsDir(), rel), filepath.Join(tmpDir, rel)); err != nil {
+			os.RemoveAll(tmpDir)
+			return err
+		}
+	}
+
+	if err := os.MkdirAll(tmpDir, 0755); err != nil {
+		return err
+	}
+	if err := os.RemoveAll(profileDir); err != nil {
+		os.RemoveAll(tmpDir)
+		return err
+	}
+	return os.Rename(tmpDir, profileDir)
+}
+
+// DiffSettingsProfile returns the files of profileDir (relative paths) that
+// differ from the live EFT Settings directory. Files missing from the profile
+// are not part of the diff - they stay as they are.
+func DiffSettingsProfile(profileDir string, filter ProfileFilter) ([]string, error) {
+	files, err := listProfileFiles(profileDir, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	var changed []string
+	for _, rel := range files {
+		saved, err := os.ReadFile(filepath.Join(profileDir, rel))
+		if err != nil {
+			return nil, err
+		}
+		live, err := os.ReadFile(filepath.Join(GetGameSettingsDir(), rel))
+		if err == nil && bytes.Equal(saved, live) {
+			continue
+		}
+		changed = append(changed, rel)
+	}
+	return changed, nil
+}
+
+// ApplySettingsProfile copies the given files (as returned by DiffSettingsProfile)
+// from profileDir into the live EFT Settings directory
+func ApplySettingsProfile(profileDir string, files []string) error {
+	for _, rel := range files {
+		if err := copyFile(filepath.Join(profileDir, rel), filepath.Join(GetGameSettingsDir(), rel)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// GameSettingsFiles returns the absolute live paths for relative profile files
+func GameSettingsFiles(files []string) []string {
+	abs := make([]string, len(files))
+	for i, rel := range files {
+		abs[i] = filepath.Join(GetGameSettingsDir(), rel)
+	}
+	return abs
+}
+
+// listProfileFiles walks dir and returns the matching regular files as
+// slash-separated relative paths. A missing dir yields no files.
+func listProfileFiles(dir string, filter ProfileFilter) ([]string, error) {
+	var files []string
+	err := filepath.WalkDir(dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) && p == dir {
+				return filepath.SkipDir
+			}
+			return err
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		rel, err := filepath.Rel(dir, p)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+		if filter.Match(rel) {
+			files = append(files, rel)
+		}
+		return nil
+	})
+	return files, err
+}
+
+// copyFile copies src to dst, creating dst's directory
+func copyFile(src, dst string) error {
+	data, err := os.ReadFile(src)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(dst, data, 0644)
+}
@@ -0,0 +1,95 @@
+package launcher
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+
+	"tarkov-account-switcher/internal/config"
+)
+
+// CaptureSession builds the per-account session from the launcher settings
+// according to the session schema. Game.ini fields are read from disk.
+func CaptureSession(launcherSettings map[string]interface{}) map[string]interface{} {
+	session := make(map[string]interface{})
+
+	for _, field := range config.SessionSchema() {
+		if field.Fixed != nil {
+			session[field.Key] = field.Fixed
+			continue
+		}
+
+		switch field.TargetOrDefault() {
+		case config.TargetGameIni:
+			val, _ := ReadGameSetting(field.TargetField())
+			if val == nil {
+				val = ""
+			}
+			session[field.Key] = val
+		default:
+			session[field.Key] = launcherSettings[field.TargetField()]
+		}
+	}
+
+	return session
+}
+
+// Login is the account logged in to the launcher and its tokens
+type Login struct {
+	Email        string
+	AccessToken  string
+	RefreshToken string
+}
+
+// HasTokens reports whether the launcher holds a complete session
+func (l Login) HasTokens() bool {
+	return l.Email != "" && l.AccessToken != "" && l.RefreshToken != ""
+}
+
+// ReadLogin reads the login and tokens from the launcher settings, using the
+// field names of the session schema
+func ReadLogin(launcherSettings map[string]interface{}) Login {
+	str := func(key string) string {
+		val, _ := launcherSettings[config.LauncherFieldName(key)].(string)
+		return val
+	}
+	return Login{
+		Email:        str(config.SessionLogin),
+		AccessToken:  str(config.SessionAccessToken),
+		RefreshToken: str(config.SessionRefreshToken),
+	}
+}
+
+var (
+	tokenNamePattern  = regexp.MustCompile(`(?i)(token|auth|session|secret|jwt|cookie|^[a-z]{1,3}t$)`)
+	tokenValuePattern = regexp.MustCompile(`^(eyJ[\w-]+\.[\w-]+\.[\w-]*|[A-Za-z0-9+_=.-]{32,})$`)
+)
+
+// UnknownTokenFields returns launcher settings keys that look like auth state
+// but are not part of the session schema. A non-empty result usually means BSG
+// added a new auth field that should be added to the schema.
+func UnknownTokenFields(launcherSettings map[string]interface{}) []string {
+	known := make(map[string]bool)
+	for _, field := range config.SessionSchema() {
+		if field.TargetOrDefault() == config.TargetLauncher {
+			known[field.TargetField()] = true
+		}
+	}
+
+	// Fields we manage ourselves or know to be harmless
+	known["tempFolder"] = true
+
+	var unknown []string
+	for key, val := range launcherSettings {
+		if known[key] {
+			continue
+		}
+		str, _ := val.(string)
+		if tokenNamePattern.MatchString(key) || (str != "" && !strings.ContainsAny(str, ` \/:`) && tokenValuePattern.MatchString(str)) {
+			unknown = append(unknown, key)
+		}
+	}
+
+	sort.Strings(unknown)
+	return unknown
+}
@@ -0,0 +1,115 @@
+package launcher
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"regexp"
+
+	"tarkov-account-switcher/internal/config"
+)
+
+// maxSessionFileSize skips large files (logs, caches) when scanning for auth state
+const maxSessionFileSize = 1 << 20
+
+// authStatePattern matches content that holds launcher auth state:
+// token keys or JWT-shaped values
+var authStatePattern = regexp.MustCompile(`(?i)"(at|rt|atet|access_?token|refresh_?token|token|session_?id)"\s*:\s*"[^"]+"|eyJ[\w-]{10,}\.[\w-]{10,}\.`)
+
+// SessionFileResult is the outcome for one discovered session file
+type SessionFileResult struct {
+	Path    string
+	Removed bool
+	Error   string
+}
+
+// SessionFileReport lists the session files found in the launcher data directory
+type SessionFileReport struct {
+	Files []SessionFileResult
+}
+
+// Removed returns the paths that were deleted
+func (r SessionFileReport) Removed() []string {
+	var removed []string
+	for _, f := range r.Files {
+		if f.Removed {
+			removed = append(removed, f.Path)
+		}
+	}
+	return removed
+}
+
+// DiscoverSessionFiles scans the launcher data directory for files that match
+// the configured session file patterns and actually contain auth state.
+// The launcher settings file itself is never included - it is rewritten, not deleted.
+func DiscoverSessionFiles() ([]string, error) {
+	settingsPath := config.LauncherSettingsPath()
+	dir := filepath.Dir(settingsPath)
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	filter := ProfileFilter{Include: config.GetSettings().SessionFilePatterns}
+
+	var found []string
+	for _, e := range entries {
+		path := filepath.Join(dir, e.Name())
+		if !e.Type().IsRegular() || path == settingsPath || !filter.Match(e.Name()) {
+			continue
+		}
+		if holdsAuthState(path) {
+			found = append(found, path)
+		}
+	}
+	return found, nil
+}
+
+// RemoveSessionFiles deletes the discovered session files and reports the result
+func RemoveSessionFiles() SessionFileReport {
+	var report SessionFileReport
+
+	files, err := DiscoverSessionFiles()
+	if err != nil {
+		report.Files = append(report.Files, SessionFileResult{
+			Path:  filepath.Dir(config.LauncherSettingsPath()),
+			Error: err.Error(),
+		})
+		return report
+	}
+
+	for _, path := range files {
+		result := SessionFileResult{Path: path}
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			result.Error = err.Error()
+		} else {
+			result.Removed = true
+		}
+		report.Files = append(report.Files, result)
+	}
+	return report
+}
+
+// holdsAuthState reports whether a file looks like it stores tokens
+func holdsAuthState(path string) bool {
+	info, err := os.Stat(path)
+	if err != nil || info.Size() == 0 || info.Size() > maxSessionFileSize {
+		return false
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return false
+	}
+
+	// Binary files (databases etc.) are not ours to delete
+	if bytes.IndexByte(data, 0) != -1 {
+		return false
+	}
+
+	return authStatePattern.Match(data)
+}
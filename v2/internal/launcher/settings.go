@@ -5,18 +5,21 @@ import (
 	"os"
 	"path/filepath"
 
+	"tarkov-account-switcher/internal/apperror"
 	"tarkov-account-switcher/internal/config"
 )
 
-// UpdateLauncherAccount updates the launcher settings with a new account email and clears session
-func UpdateLauncherAccount(email string) error {
+// UpdateLauncherAccount updates the launcher settings with a new account email and clears session.
+// The returned report lists which session files were deleted.
+func UpdateLauncherAccount(email string) (SessionFileReport, error) {
 	paths := config.GetPaths()
-	launcherDataPath := filepath.Dir(paths.LauncherSettingsPath)
+	settingsPath := config.LauncherSettingsPath()
+	launcherDataPath := filepath.Dir(settingsPath)
 
 	// Read existing settings or create new
 	var settings map[string]interface{}
 
-	data, err := os.ReadFile(paths.LauncherSettingsPath)
+	data, err := os.ReadFile(settingsPath)
 	if err == nil {
 		json.Unmarshal(data, &settings)
 	}
@@ -26,88 +29,78 @@ func UpdateLauncherAccount(email string) error {
 	}
 
 	// Update login email and settings
-	settings["login"] = email
+	settings[config.LauncherFieldName(config.SessionLogin)] = email
 	settings["saveLogin"] = true
 	settings["keepLoggedIn"] = true
-	settings["tempFolder"] = paths.TempFolder
+	settings["tempFolder"] = config.LauncherPathFor(paths.TempFolder)
 
 	// CRITICAL: Delete session tokens to force fresh login
-	delete(settings, "at")
-	delete(settings, "rt")
-	delete(settings, "atet")
+	delete(settings, config.LauncherFieldName(config.SessionAccessToken))
+	delete(settings, config.LauncherFieldName(config.SessionRefreshToken))
+	delete(settings, config.LauncherFieldName(config.SessionTokenExpiry))
 
 	// Ensure directory exists
 	if err := os.MkdirAll(launcherDataPath, 0755); err != nil {
-		return err
+		return SessionFileReport{}, apperror.New(apperror.SettingsUnwritable, err)
 	}
 
 	// Write settings
 	settingsData, err := json.MarshalIndent(settings, "", "  ")
 	if err != nil {
-		return err
-	}
-
-	if err := os.WriteFile(paths.LauncherSettingsPath, settingsData, 0644); err != nil {
-		return err
+		return SessionFileReport{}, err
 	}
 
-	// Delete session files to force re-login
-	sessionFiles := []string{
-		"user.json",
-		"session",
-		"token",
-		".session",
-		"auth",
-		"auth.json",
-		"login",
-		"login.json",
+	if err := os.WriteFile(settingsPath, settingsData, 0644); err != nil {
+		return SessionFileReport{}, apperror.New(apperror.SettingsUnwritable, err)
 	}
 
-	for _, file := range sessionFiles {
-		filePath := filepath.Join(launcherDataPath, file)
-		os.Remove(filePath) // Ignore errors
-	}
-
-	return nil
+	// Delete session files that actually hold auth state to force re-login
+	return RemoveSessionFiles(), nil
 }
 
-// RestoreLauncherSession restores a saved launcher session
-func RestoreLauncherSession(sessionData json.RawMessage) error {
+// RestoreLauncherSession restores a saved launcher session.
+// If game is set it overrides the saved selectedGame.
+func RestoreLauncherSession(sessionData json.RawMessage, game string) error {
 	paths := config.GetPaths()
+	settingsPath := config.LauncherSettingsPath()
 
 	// Parse saved session
 	var savedSession map[string]interface{}
 	if err := json.Unmarshal(sessionData, &savedSession); err != nil {
-		return err
+		return apperror.New(apperror.SessionUndecryptable, err)
 	}
 
 	// Read existing launcher settings to preserve game state
 	var existingSettings map[string]interface{}
 
-	data, err := os.ReadFile(paths.LauncherSettingsPath)
+	data, err := os.ReadFile(settingsPath)
 	if err == nil {
 		json.Unmarshal(data, &existingSettings)
 	}
 
 	if existingSettings == nil {
 		existingSettings = make(map[string]interface{})
-		// Copy all from saved session as base
-		for k, v := range savedSession {
-			existingSettings[k] = v
-		}
 	}
 
-	// Restore auth-related fields AND selectedGame from saved session
+	// Restore the schema's launcher fields from saved session
 	// Preserve everything else (games, UI preferences) from current launcher state
-	authFields := []string{"login", "at", "rt", "atet", "keepLoggedIn", "saveLogin", "selectedGame", "sysInfCheck"}
-	for _, field := range authFields {
-		if val, ok := savedSession[field]; ok {
-			existingSettings[field] = val
+	schema := config.SessionSchema()
+	for _, field := range schema {
+		if field.TargetOrDefault() != config.TargetLauncher {
+			continue
 		}
+		if val, ok := savedSession[field.Key]; ok {
+			existingSettings[field.TargetField()] = val
+		}
+	}
+
+	// Account default game wins over whatever was selected when the session was captured
+	if game != "" {
+		existingSettings["selectedGame"] = game
 	}
 
 	// ALWAYS use our own temp folder
-	existingSettings["tempFolder"] = paths.TempFolder
+	existingSettings["tempFolder"] = config.LauncherPathFor(paths.TempFolder)
 
 	// Write settings
 	settingsData, err := json.MarshalIndent(existingSettings, "", "  ")
@@ -115,30 +108,36 @@ func RestoreLauncherSession(sessionData json.RawMessage) error {
 		return err
 	}
 
-	if err := os.WriteFile(paths.LauncherSettingsPath, settingsData, 0644); err != nil {
-		return err
+	if err := os.WriteFile(settingsPath, settingsData, 0644); err != nil {
+		return apperror.New(apperror.SettingsUnwritable, err)
 	}
 
-	// Restore ingame background (EnvironmentUiType) to Game.ini
-	if envType, ok := savedSession["environmentUiType"].(string); ok && envType != "" {
-		WriteEnvironmentUiType(envType)
+	// Restore Game.ini fields (e.g. ingame background). Empty values mean
+	// nothing was captured, so the current setting is kept.
+	gameValues := make(map[string]interface{})
+	for _, field := range schema {
+		if field.TargetOrDefault() != config.TargetGameIni {
+			continue
+		}
+		if val, ok := savedSession[field.Key]; ok && val != nil && val != "" {
+			gameValues[field.TargetField()] = val
+		}
 	}
+	WriteGameSettings(gameValues)
 
 	return nil
 }
 
 // ReadLauncherSettings reads the current launcher settings
 func ReadLauncherSettings() (map[string]interface{}, error) {
-	paths := config.GetPaths()
-
-	data, err := os.ReadFile(paths.LauncherSettingsPath)
+	data, err := os.ReadFile(config.LauncherSettingsPath())
 	if err != nil {
-		return nil, err
+		return nil, apperror.New(apperror.SettingsUnreadable, err)
 	}
 
 	var settings map[string]interface{}
 	if err := json.Unmarshal(data, &settings); err != nil {
-		return nil, err
+		return nil, apperror.New(apperror.SettingsUnreadable, err)
 	}
 
 	return settings, nil
@@ -146,31 +145,29 @@ func ReadLauncherSettings() (map[string]interface{}, error) {
 
 // GetGameSettingsPath returns the path to the EFT Game.ini file
 func GetGameSettingsPath() string {
-	appData := os.Getenv("APPDATA")
+	appData := config.GetGameDirs().RoamingAppData
 	return filepath.Join(appData, "Battlestate Games", "Escape from Tarkov", "Settings", "Game.ini")
 }
 
-// ReadEnvironmentUiType reads the current EnvironmentUiType (ingame background) from Game.ini
-func ReadEnvironmentUiType() string {
+// ReadGameSetting reads a single top-level value from Game.ini
+func ReadGameSetting(key string) (interface{}, bool) {
 	data, err := os.ReadFile(GetGameSettingsPath())
 	if err != nil {
-		return ""
+		return nil, false
 	}
 
 	var gameSettings map[string]interface{}
 	if err := json.Unmarshal(data, &gameSettings); err != nil {
-		return ""
+		return nil, false
 	}
 
-	if envType, ok := gameSettings["EnvironmentUiType"].(string); ok {
-		return envType
-	}
-	return ""
+	val, ok := gameSettings[key]
+	return val, ok
 }
 
-// WriteEnvironmentUiType writes the EnvironmentUiType (ingame background) to Game.ini
-func WriteEnvironmentUiType(envType string) error {
-	if envType == "" {
+// WriteGameSettings writes top-level values (e.g. EnvironmentUiType) to Game.ini
+func WriteGameSettings(values map[string]interface{}) error {
+	if len(values) == 0 {
 		return nil // Nothing to restore
 	}
 
@@ -187,8 +184,9 @@ func WriteEnvironmentUiType(envType string) error {
 		return err
 	}
 
-	// Update EnvironmentUiType
-	gameSettings["EnvironmentUiType"] = envType
+	for key, val := range values {
+		gameSettings[key] = val
+	}
 
 	// Write back
 	settingsData, err := json.MarshalIndent(gameSettings, "", "  ")
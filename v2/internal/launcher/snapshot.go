@@ -0,0 +1,61 @@
+package launcher
+
+import (
+	"os"
+	"path/filepath"
+
+	"tarkov-account-switcher/internal/config"
+)
+
+// Snapshot holds the raw contents of launcher-owned files taken before a switch
+// modifies them. A nil entry means the file did not exist at snapshot time.
+type Snapshot struct {
+	files map[string][]byte
+}
+
+// TakeSnapshot captures the launcher settings file and Game.ini
+func TakeSnapshot() (*Snapshot, error) {
+	return SnapshotFiles(config.LauncherSettingsPath(), GetGameSettingsPath())
+}
+
+// SnapshotFiles captures the given files. Missing files are recorded so that
+// Restore removes them again instead of leaving half-written copies behind.
+func SnapshotFiles(files ...string) (*Snapshot, error) {
+	s := &Snapshot{files: make(map[string][]byte, len(files))}
+	for _, path := range files {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			if os.IsNotExist(err) {
+				s.files[path] = nil
+				continue
+			}
+			return nil, err
+		}
+		s.files[path] = data
+	}
+	return s, nil
+}
+
+// Restore writes every captured file back to disk. It keeps going after a
+// failure so one locked file does not prevent the others from being restored,
+// and returns the first error encountered.
+func (s *Snapshot) Restore() error {
+	var firstErr error
+	for path, data := range s.files {
+		var err error
+		if data == nil {
+			err = os.Remove(path)
+			if os.IsNotExist(err) {
+				err = nil
+			}
+		} else {
+			if err = os.MkdirAll(filepath.Dir(path), 0755); err == nil {
+				err = os.WriteFile(path, data, 0644)
+			}
+		}
+		if err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
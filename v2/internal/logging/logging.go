@@ -0,0 +1,82 @@
+// Package logging writes the application log: leveled and structured
+// (log/slog), redacted by the redact package and rotated in the data
+// directory. Packages log through the slog default logger.
+package logging
+
+import (
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"tarkov-account-switcher/internal/config"
+	"tarkov-account-switcher/internal/redact"
+)
+
+const (
+	fileName = "app.log"
+	maxBytes = 1 << 20 // per file
+	keep     = 5       // rotated files kept besides the current one
+)
+
+var current *rotatingFile
+
+// Init opens the log in the data directory and makes it the slog default
+func Init() error {
+	if err := os.MkdirAll(config.GetPaths().LogDir, 0700); err != nil {
+		return err
+	}
+	file, err := openRotating(Path(), maxBytes, keep)
+	if err != nil {
+		return err
+	}
+	current = file
+
+	handler := slog.NewTextHandler(file, &slog.HandlerOptions{
+		Level:       level(config.GetSettings().LogLevel),
+		ReplaceAttr: redact.Attr,
+	})
+	slog.SetDefault(slog.New(handler))
+	return nil
+}
+
+// Close flushes and closes the log file
+func Close() {
+	if current != nil {
+		current.Close()
+	}
+}
+
+// Path returns the current log file
+func Path() string {
+	return filepath.Join(config.GetPaths().LogDir, fileName)
+}
+
+// Files returns the log files that exist, newest first
+func Files() []string {
+	var files []string
+	for i := 0; i <= keep; i++ {
+		path := Path()
+		if i > 0 {
+			path = rotatedName(path, i)
+		}
+		if _, err := os.Stat(path); err == nil {
+			files = append(files, path)
+		}
+	}
+	return files
+}
+
+// level parses the logLevel setting (debug, info, warn, error)
+func level(name string) slog.Level {
+	switch strings.ToLower(name) {
+	case "debug":
+		return slog.LevelDebug
+	case "warn":
+		return slog.LevelWarn
+	case "error":
+		return slog.LevelError
+	default:
+		return slog.LevelInfo
+	}
+}
@@ -0,0 +1,97 @@
+package logging
+
+import (
+	"os"
+	"strconv"
+	"sync"
+)
+
+// rotatingFile is an append-only log file that is renamed to path.1,
+// path.2, ... once it reaches maxBytes. The oldest file beyond keep is
+// deleted.
+type rotatingFile struct {
+	mu       sync.Mutex
+	path     string
+	maxBytes int64
+	keep     int
+	file     *os.File
+	size     int64
+}
+
+func openRotating(path string, maxBytes int64, keep int) (*rotatingFile, error) {
+	r := &rotatingFile{path: path, maxBytes: maxBytes, keep: keep}
+	if err := r.open(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+func (r *rotatingFile) open() error {
+	file, err := os.OpenFile(r.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		return err
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return err
+	}
+	r.file = file
+	r.size = info.Size()
+	return nil
+}
+
+// Write appends p, rotating first if p would not fit
+func (r *rotatingFile) Write(p []byte) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.file == nil {
+		return 0, os.ErrClosed
+	}
+	if r.size > 0 && r.size+int64(len(p)) > r.maxBytes {
+		if err := r.rotate(); err != nil {
+			return 0, err
+		}
+	}
+
+	n, err := r.file.Write(p)
+	r.size += int64(n)
+	return n, err
+}
+
+func (r *rotatingFile) rotate() error {
+	r.file.Close()
+	r.file = nil
+
+	os.Remove(rotatedName(r.path, r.keep))
+	for i := r.keep - 1; i >= 1; i-- {
+		os.Rename(rotatedName(r.path, i), rotatedName(r.path, i+1))
+	}
+	if err := os.Rename(r.path, rotatedName(r.path, 1)); err != nil && !os.IsNotExist(err) {
+		// E.g. a virus scanner holds the log open on Windows: keep
+		// appending to the full file and retry with the next write
+		if openErr := r.open(); openErr != nil {
+			return openErr
+		}
+		return nil
+	}
+	return r.open()
+}
+
+// Close closes the current file
+func (r *rotatingFile) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.file == nil {
+		return nil
+	}
+	err := r.file.Close()
+	r.file = nil
+	return err
+}
+
+func rotatedName(path string, n int) string {
+	return path + "." + strconv.Itoa(n)
+}
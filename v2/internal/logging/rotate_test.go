@@ -0,0 +1,153 @@
+package logging
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRotate(t *testing.T) {
+	tests := []struct {
+		name   string
+		max    int64
+		keep   int
+		writes []string
+		files  map[string]string // suffix ("" for the log itself) -> content
+	}{
+		{
+			name:   "fits",
+			max:    20,
+			keep:   2,
+			writes: []string{"aaaa\n", "bbbb\n"},
+			files:  map[string]string{"": "aaaa\nbbbb\n"},
+		},
+		{
+			name:   "rotates before a write that would not fit",
+			max:    10,
+			keep:   2,
+			writes: []string{"aaaa\n", "bbbb\n", "cccc\n"},
+			files:  map[string]string{"": "cccc\n", ".1": "aaaa\nbbbb\n"},
+		},
+		{
+			name:   "older files move up",
+			max:    5,
+			keep:   3,
+			writes: []string{"aaaa\n", "bbbb\n", "cccc\n"},
+			files:  map[string]string{"": "cccc\n", ".1": "bbbb\n", ".2": "aaaa\n"},
+		},
+		{
+			name:   "oldest beyond keep is deleted",
+			max:    5,
+			keep:   2,
+			writes: []string{"aaaa\n", "bbbb\n", "cccc\n", "dddd\n"},
+			files:  map[string]string{"": "dddd\n", ".1": "cccc\n", ".2": "bbbb\n", ".3": ""},
+		},
+		{
+			name:   "a line longer than max goes into an empty file",
+			max:    4,
+			keep:   1,
+			writes: []string{"a very long line\n"},
+			files:  map[string]string{"": "a very long line\n", ".1": ""},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "app.log")
+			r, err := openRotating(path, tt.max, tt.keep)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, line := range tt.writes {
+				if _, err := r.Write([]byte(line)); err != nil {
+					t.Fatalf("Write(%q) = %v", line, err)
+				}
+			}
+			if err := r.Close(); err != nil {
+				t.Fatal(err)
+			}
+
+			// An empty want means the file must not exist
+			for suffix, want := range tt.files {
+				data, err := os.ReadFile(path + suffix)
+				if want == "" {
+					if !os.IsNotExist(err) {
+						t.Errorf("app.log%s exists: %q", suffix, data)
+					}
+					continue
+				}
+				if err != nil || string(data) != want {
+					t.Errorf("app.log%s = %q, %v, want %q", suffix, data, err, want)
+				}
+			}
+		})
+	}
+}
+
+func TestRotateAppendsToExistingLog(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	if err := os.WriteFile(path, []byte("old\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	r, err := openRotating(path, 8, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+
+	// The existing size counts: "old\n" + "newer\n" exceeds 8 bytes
+	if _, err := r.Write([]byte("newer\n")); err != nil {
+		t.Fatal(err)
+	}
+	if data, _ := os.ReadFile(rotatedName(path, 1)); string(data) != "old\n" {
+		t.Errorf("app.log.1 = %q, want the old log", data)
+	}
+	if data, _ := os.ReadFile(path); string(data) != "newer\n" {
+		t.Errorf("app.log = %q, want the new line", data)
+	}
+}
+
+func TestRotateRenameFails(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	r, err := openRotating(path, 10, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+
+	// A non-empty directory in place of app.log.1 can neither be removed
+	// nor replaced, so the rename fails
+	blocker := rotatedName(path, 1)
+	if err := os.MkdirAll(filepath.Join(blocker, "held"), 0700); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, line := range []string{"first line\n", "second line\n", "third line\n"} {
+		if _, err := r.Write([]byte(line)); err != nil {
+			t.Fatalf("Write(%q) = %v, want logging to go on", line, err)
+		}
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := string(data); got != "first line\nsecond line\nthird line\n" {
+		t.Errorf("log = %q, want all lines appended", got)
+	}
+	if info, err := os.Stat(blocker); err != nil || !info.IsDir() {
+		t.Errorf("%s was replaced: %v", blocker, err)
+	}
+
+	// Rotation resumes once the rename works again
+	if err := os.RemoveAll(blocker); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := r.Write([]byte("fourth line\n")); err != nil {
+		t.Fatal(err)
+	}
+	rotated, err := os.ReadFile(blocker)
+	if err != nil || !strings.HasPrefix(string(rotated), "first line") {
+		t.Errorf("%s = %q, %v, want the full log", blocker, rotated, err)
+	}
+}
@@ -0,0 +1,118 @@
+// Package notify shows desktop notifications through a platform backend:
+// the tray icon balloon on Windows (registered by the app) and the
+// freedesktop notification service over D-Bus on Linux.
+package notify
+
+import (
+	"sync"
+
+	"tarkov-account-switcher/internal/config"
+	"tarkov-account-switcher/internal/events"
+	"tarkov-account-switcher/internal/i18n"
+)
+
+// Notification categories, each with a toggle in config.NotificationSettings
+const (
+	CategorySessionCaptured = "sessionCaptured"
+	CategoryCaptureTimeout  = "captureTimeout"
+	CategorySwitchFailed    = "switchFailed"
+	CategorySessionExpiring = "sessionExpiring"
+)
+
+// Notification is one desktop notification
+type Notification struct {
+	Category string // "" for notifications that are always shown
+	Message  string
+	IsError  bool
+}
+
+// Notifier shows notifications on the desktop
+type Notifier interface {
+	Notify(title, message string, isError bool) error
+}
+
+// NotifierFunc adapts a function to a Notifier
+type NotifierFunc func(title, message string, isError bool) error
+
+// Notify calls f
+func (f NotifierFunc) Notify(title, message string, isError bool) error {
+	return f(title, message, isError)
+}
+
+var (
+	mu       sync.Mutex
+	notifier = defaultNotifier()
+)
+
+// SetNotifier replaces the platform backend
+func SetNotifier(n Notifier) {
+	mu.Lock()
+	notifier = n
+	mu.Unlock()
+}
+
+// Send shows a notification unless its category is turned off
+func Send(n Notification) error {
+	if !Enabled(n.Category) {
+		return nil
+	}
+
+	mu.Lock()
+	backend := notifier
+	mu.Unlock()
+
+	if backend == nil {
+		return nil
+	}
+	return backend.Notify(i18n.T(i18n.NotifyTitle), n.Message, n.IsError)
+}
+
+// Enabled reports whether notifications of a category are shown
+func Enabled(category string) bool {
+	settings := config.GetSettings().Notifications
+	switch category {
+	case CategorySessionCaptured:
+		return settings.SessionCaptured
+	case CategoryCaptureTimeout:
+		return settings.CaptureTimeout
+	case CategorySwitchFailed:
+		return settings.SwitchFailed
+	case CategorySessionExpiring:
+		return settings.SessionExpiring
+	default:
+		return true
+	}
+}
+
+// FromEvent builds the notification for a bus event. ok is false for
+// events that are not notified.
+func FromEvent(event events.Event) (n Notification, ok bool) {
+	name := map[string]string{"name": event.AccountName}
+
+	switch event.Type {
+	case events.SessionCaptured:
+		return Notification{
+			Category: CategorySessionCaptured,
+			Message:  i18n.TF(i18n.NotifyCaptured, name),
+		}, true
+	case events.WatcherTimeout:
+		return Notification{
+			Category: CategoryCaptureTimeout,
+			Message:  i18n.TF(i18n.NotifyCaptureTimeout, name),
+			IsError:  true,
+		}, true
+	case events.GameNotStarted:
+		return Notification{
+			Category: CategorySwitchFailed,
+			Message:  i18n.TF(i18n.NotifyGameNotStarted, map[string]string{"name": event.AccountName, "error": event.Error}),
+			IsError:  true,
+		}, true
+	case events.SwitchFailed:
+		return Notification{
+			Category: CategorySwitchFailed,
+			Message:  i18n.TF(i18n.NotifySwitchFailed, map[string]string{"error": event.Error}),
+			IsError:  true,
+		}, true
+	}
+	return Notification{}, false
+}
@@ -0,0 +1,45 @@
+//go:build !windows
+
+package notify
+
+import (
+	"github.com/godbus/dbus/v5"
+)
+
+// dbusNotifier talks to org.freedesktop.Notifications on the session bus
+type dbusNotifier struct{}
+
+// Urgency hint values of the notification spec
+const (
+	urgencyNormal   byte = 1
+	urgencyCritical byte = 2
+)
+
+func defaultNotifier() Notifier {
+	return dbusNotifier{}
+}
+
+// Notify sends the notification; fails if no notification service runs
+func (dbusNotifier) Notify(title, message string, isError bool) error {
+	conn, err := dbus.SessionBus()
+	if err != nil {
+		return err
+	}
+
+	icon, urgency := "dialog-information", urgencyNormal
+	if isError {
+		icon, urgency = "dialog-error", urgencyCritical
+	}
+
+	obj := conn.Object("org.freedesktop.Notifications", "/org/freedesktop/Notifications")
+	return obj.Call("org.freedesktop.Notifications.Notify", 0,
+		title,      // app_name
+		uint32(0),  // replaces_id
+		icon,       // app_icon
+		title,      // summary
+		message,    // body
+		[]string{}, // actions
+		map[string]dbus.Variant{"urgency": dbus.MakeVariant(urgency)},
+		int32(-1), // expire_timeout: server default
+	).Err
+}
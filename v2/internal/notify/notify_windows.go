@@ -0,0 +1,7 @@
+package notify
+
+// No built-in backend: the app registers the tray balloon
+// with SetNotifier once the tray icon exists.
+func defaultNotifier() Notifier {
+	return nil
+}
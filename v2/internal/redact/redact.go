@@ -0,0 +1,90 @@
+// Package redact removes secrets from text that leaves the app: log files,
+// support bundles and diagnostics. Tokens are replaced, emails masked.
+package redact
+
+import (
+	"fmt"
+	"log/slog"
+	"regexp"
+	"strings"
+
+	"tarkov-account-switcher/internal/config"
+)
+
+// Placeholder replaces redacted values
+const Placeholder = "[redacted]"
+
+var (
+	emailPattern = regexp.MustCompile(`[A-Za-z0-9._%+\-]+@[A-Za-z0-9.\-]+\.[A-Za-z]{2,}`)
+
+	// JSON fields and key=value pairs whose value is a secret
+	secretFieldPattern = regexp.MustCompile(`(?i)("(?:at|rt|atet|token|accessToken|refreshToken|sysInfCheck|encryptedSession|password)"\s*:\s*)"[^"]*"`)
+	secretPairPattern  = regexp.MustCompile(`(?i)\b(at|rt|token|access_token|refresh_token|password|bearer)([=:]\s*|\s+)[^\s&",]+`)
+
+	// JWTs and long opaque strings mixing letters and digits
+	jwtPattern    = regexp.MustCompile(`eyJ[A-Za-z0-9_\-]+\.[A-Za-z0-9_\-]+\.[A-Za-z0-9_\-]*`)
+	opaquePattern = regexp.MustCompile(`[A-Za-z0-9+=_\-]{24,}`)
+)
+
+// sensitiveKeys are attribute keys whose values are never written
+var sensitiveKeys = map[string]bool{
+	"at":               true,
+	"rt":               true,
+	"atet":             true,
+	"token":            true,
+	"accesstoken":      true,
+	"refreshtoken":     true,
+	"session":          true,
+	"launchersession":  true,
+	"encryptedsession": true,
+	"password":         true,
+	"authorization":    true,
+	"key":              true,
+}
+
+// emailKeys are attribute keys holding an email address
+var emailKeys = map[string]bool{
+	"email": true,
+	"login": true,
+}
+
+// String masks emails and replaces tokens in free text
+func String(s string) string {
+	s = secretFieldPattern.ReplaceAllString(s, `$1"`+Placeholder+`"`)
+	s = secretPairPattern.ReplaceAllString(s, `$1$2`+Placeholder)
+	s = jwtPattern.ReplaceAllString(s, Placeholder)
+	s = opaquePattern.ReplaceAllStringFunc(s, func(m string) string {
+		if isOpaque(m) {
+			return Placeholder
+		}
+		return m
+	})
+	return emailPattern.ReplaceAllStringFunc(s, config.HideEmail)
+}
+
+// isOpaque reports whether a long word looks like a token rather than a
+// name or path: it mixes letters and digits
+func isOpaque(s string) bool {
+	return strings.ContainsAny(s, "0123456789") &&
+		strings.ContainsAny(strings.ToLower(s), "abcdefghijklmnopqrstuvwxyz")
+}
+
+// Attr redacts a log attribute; use it as slog.HandlerOptions.ReplaceAttr
+func Attr(groups []string, a slog.Attr) slog.Attr {
+	key := strings.ToLower(a.Key)
+	switch {
+	case sensitiveKeys[key]:
+		return slog.String(a.Key, Placeholder)
+	case emailKeys[key]:
+		return slog.String(a.Key, config.HideEmail(a.Value.String()))
+	}
+
+	switch a.Value.Kind() {
+	case slog.KindString:
+		return slog.String(a.Key, String(a.Value.String()))
+	case slog.KindAny:
+		// Errors and other values are written through their text form
+		return slog.String(a.Key, String(fmt.Sprint(a.Value.Any())))
+	}
+	return a
+}
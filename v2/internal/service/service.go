@@ -0,0 +1,236 @@
+// Package service is the layer between the account core and its front ends:
+// the Wails app and the HTTP API share the DTOs and operations here, so both
+// see the same data (e.g. masked emails in streamer mode).
+package service
+
+import (
+	"context"
+	"time"
+
+	"tarkov-account-switcher/internal/accounts"
+	"tarkov-account-switcher/internal/config"
+	"tarkov-account-switcher/internal/launcher"
+)
+
+// AccountDTO is sent to the front ends (no raw session data exposed)
+type AccountDTO struct {
+	ID              string `json:"id"`
+	Name            string `json:"name"`
+	Email           string `json:"email"`
+	HasSession      bool   `json:"hasSession"`
+	SessionCaptured string `json:"sessionCaptured"`
+
+	CacheTargets  []string `json:"cacheTargets"`
+	CacheOverride bool     `json:"cacheOverride"`
+	DefaultGame   string   `json:"defaultGame"`
+	GameProfile   bool     `json:"gameProfile"`
+	CefProfile    bool     `json:"cefProfile"`
+	UnknownFields []string `json:"unknownFields"`
+
+	RecentSwitches  int `json:"recentSwitches"`  // switches inside the policy window
+	CooldownSeconds int `json:"cooldownSeconds"` // until switching is within the policy again
+}
+
+// ToAccountDTO converts an account for the front ends
+func ToAccountDTO(acc accounts.Account) AccountDTO {
+	cooldown := acc.Cooldown(time.Now())
+	return AccountDTO{
+		ID:              acc.ID,
+		Name:            acc.Name,
+		Email:           config.MaskEmail(acc.Email),
+		HasSession:      acc.HasSession(),
+		SessionCaptured: acc.SessionCaptured,
+
+		CacheTargets:  acc.EffectiveCacheTargets(),
+		CacheOverride: acc.CacheTargets != nil,
+		DefaultGame:   acc.DefaultGame,
+		GameProfile:   acc.GameProfile,
+		CefProfile:    acc.CefProfile,
+		UnknownFields: acc.UnknownFields,
+
+		RecentSwitches:  cooldown.Switches,
+		CooldownSeconds: cooldownSeconds(cooldown),
+	}
+}
+
+// cooldownSeconds returns the remaining cooldown in whole seconds, 0 if none
+func cooldownSeconds(c accounts.Cooldown) int {
+	if !c.Exceeded() {
+		return 0
+	}
+	return int((c.Remaining + time.Second - 1) / time.Second)
+}
+
+// ListAccounts returns all accounts as DTOs
+func ListAccounts() ([]AccountDTO, error) {
+	accs, err := accounts.GetAccounts()
+	if err != nil {
+		return nil, err
+	}
+	dtos := make([]AccountDTO, len(accs))
+	for i, acc := range accs {
+		dtos[i] = ToAccountDTO(acc)
+	}
+	return dtos, nil
+}
+
+// SwitchResultDTO is the result of a switch operation
+type SwitchResultDTO struct {
+	Success     bool   `json:"success"`
+	AccountName string `json:"accountName"`
+	Email       string `json:"email"`
+	HasSession  bool   `json:"hasSession"`
+	Message     string `json:"message"`
+	ErrorCode   string `json:"errorCode"` // apperror code, empty on success
+	Error       string `json:"error"`     // localized message for ErrorCode
+
+	Cache               CacheReportDTO `json:"cache"`
+	Warnings            []string       `json:"warnings"`
+	RemovedSessionFiles []string       `json:"removedSessionFiles"`
+
+	NeedsConfirmation bool `json:"needsConfirmation"`
+	CooldownSeconds   int  `json:"cooldownSeconds"`
+
+	DurationMs int64           `json:"durationMs"`
+	Steps      []SwitchStepDTO `json:"steps"`
+}
+
+// SwitchStepDTO is one timed step of a switch
+type SwitchStepDTO struct {
+	Name       string `json:"name"`
+	DurationMs int64  `json:"durationMs"`
+	Error      string `json:"error"`
+}
+
+// Switch switches to the account with the given ID. An empty game uses the
+// account's default; confirmed switches despite a confirm switch policy.
+// Ending ctx cancels the switch.
+func Switch(ctx context.Context, id, game string, confirmed bool) SwitchResultDTO {
+	if confirmed {
+		return ToSwitchResultDTO(accounts.ConfirmSwitchAccountGame(ctx, id, game))
+	}
+	return ToSwitchResultDTO(accounts.SwitchAccountGame(ctx, id, game))
+}
+
+// ToSwitchResultDTO converts a switch result for the front ends
+func ToSwitchResultDTO(result *accounts.SwitchResult) SwitchResultDTO {
+	dto := SwitchResultDTO{
+		Success:     result.Success,
+		AccountName: result.AccountName,
+		Email:       config.MaskEmail(result.Email),
+		HasSession:  result.HasSession,
+		Message:     result.Message,
+		ErrorCode:   string(result.Code),
+		Error:       result.Error,
+
+		Cache:               ToCacheReportDTO(result.Cache),
+		Warnings:            result.Warnings,
+		RemovedSessionFiles: result.SessionFiles.Removed(),
+
+		NeedsConfirmation: result.NeedsConfirmation,
+		CooldownSeconds:   cooldownSeconds(result.Cooldown),
+
+		Steps: []SwitchStepDTO{},
+	}
+	if result.Trace != nil {
+		dto.DurationMs = result.Trace.DurationMs
+		for _, step := range result.Trace.Steps {
+			dto.Steps = append(dto.Steps, SwitchStepDTO{
+				Name:       step.Name,
+				DurationMs: step.DurationMs,
+				Error:      step.Error,
+			})
+		}
+	}
+	return dto
+}
+
+// CacheEntryDTO describes one cleared (or previewed) cache target
+type CacheEntryDTO struct {
+	Target  string `json:"target"`
+	Path    string `json:"path"`
+	Exists  bool   `json:"exists"`
+	Size    int64  `json:"size"`
+	Removed bool   `json:"removed"`
+	Error   string `json:"error"`
+}
+
+// CacheReportDTO is the result of a cache clear or dry run
+type CacheReportDTO struct {
+	DryRun    bool            `json:"dryRun"`
+	Entries   []CacheEntryDTO `json:"entries"`
+	TotalSize int64           `json:"totalSize"`
+}
+
+// ToCacheReportDTO converts a cache report for the front ends
+func ToCacheReportDTO(report launcher.CacheReport) CacheReportDTO {
+	entries := make([]CacheEntryDTO, len(report.Entries))
+	for i, e := range report.Entries {
+		entries[i] = CacheEntryDTO{
+			Target:  e.Target,
+			Path:    e.Path,
+			Exists:  e.Exists,
+			Size:    e.Size,
+			Removed: e.Removed,
+			Error:   e.Error,
+		}
+	}
+	return CacheReportDTO{
+		DryRun:    report.DryRun,
+		Entries:   entries,
+		TotalSize: report.TotalSize,
+	}
+}
+
+// WatcherDTO tells whether the session watcher waits for a login
+type WatcherDTO struct {
+	Watching  bool   `json:"watching"`
+	AccountID string `json:"accountId"`
+}
+
+// WatcherStatus returns the session watcher state
+func WatcherStatus() WatcherDTO {
+	return WatcherDTO{
+		Watching:  accounts.IsWatching(),
+		AccountID: accounts.GetWatchingAccountID(),
+	}
+}
+
+// LoginDTO is the account currently logged in to the launcher
+type LoginDTO struct {
+	LoggedIn    bool   `json:"loggedIn"`
+	Email       string `json:"email"`
+	AccountID   string `json:"accountId"` // empty if the login is not a saved account
+	AccountName string `json:"accountName"`
+}
+
+// CurrentLogin reads the launcher settings for the logged in account
+func CurrentLogin() (LoginDTO, error) {
+	settings, err := launcher.ReadLauncherSettings()
+	if err != nil {
+		return LoginDTO{}, err
+	}
+
+	current := launcher.ReadLogin(settings)
+	login := current.Email
+	dto := LoginDTO{
+		LoggedIn: login != "" && current.AccessToken != "",
+		Email:    config.MaskEmail(login),
+	}
+	if login == "" {
+		return dto, nil
+	}
+
+	accs, err := accounts.GetAccounts()
+	if err != nil {
+		return dto, err
+	}
+	for _, acc := range accs {
+		if acc.Email == login {
+			dto.AccountID = acc.ID
+			dto.AccountName = acc.Name
+			break
+		}
+	}
+	return dto, nil
+}
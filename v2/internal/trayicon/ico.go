@@ -0,0 +1,203 @@
+package trayicon
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"image"
+	"image/png"
+)
+
+// ICO layout: ICONDIR, one ICONDIRENTRY per image, then the image data.
+// Each image is either a PNG or a DIB (BITMAPINFOHEADER, bottom-up pixels,
+// AND mask) with a doubled height.
+const (
+	iconDirSize   = 6
+	iconEntrySize = 16
+	bmpHeaderSize = 40
+)
+
+var pngMagic = []byte("\x89PNG\r\n\x1a\n")
+
+type iconDir struct {
+	Reserved uint16
+	Type     uint16 // 1 = icon
+	Count    uint16
+}
+
+type iconEntry struct {
+	Width      uint8 // 0 means 256
+	Height     uint8
+	ColorCount uint8
+	Reserved   uint8
+	Planes     uint16
+	BitCount   uint16
+	Size       uint32
+	Offset     uint32
+}
+
+type bitmapInfoHeader struct {
+	Size          uint32
+	Width         int32
+	Height        int32
+	Planes        uint16
+	BitCount      uint16
+	Compression   uint32
+	SizeImage     uint32
+	XPelsPerMeter int32
+	YPelsPerMeter int32
+	ClrUsed       uint32
+	ClrImportant  uint32
+}
+
+// DecodeICO returns every image of an ICO file. Entries may be PNG or
+// uncompressed 32-bit DIBs; other formats are rejected.
+func DecodeICO(data []byte) ([]*image.NRGBA, error) {
+	var dir iconDir
+	if err := binary.Read(bytes.NewReader(data), binary.LittleEndian, &dir); err != nil {
+		return nil, errors.New("not an icon file")
+	}
+	if dir.Reserved != 0 || dir.Type != 1 || dir.Count == 0 {
+		return nil, errors.New("not an icon file")
+	}
+
+	images := make([]*image.NRGBA, 0, dir.Count)
+	for i := 0; i < int(dir.Count); i++ {
+		start := iconDirSize + i*iconEntrySize
+		if start+iconEntrySize > len(data) {
+			return nil, errors.New("truncated icon directory")
+		}
+		var entry iconEntry
+		if err := binary.Read(bytes.NewReader(data[start:start+iconEntrySize]), binary.LittleEndian, &entry); err != nil {
+			return nil, errors.New("truncated icon directory")
+		}
+
+		end := uint64(entry.Offset) + uint64(entry.Size)
+		if end > uint64(len(data)) {
+			return nil, errors.New("truncated icon image")
+		}
+		img, err := decodeEntry(data[entry.Offset:end])
+		if err != nil {
+			return nil, err
+		}
+		images = append(images, img)
+	}
+	return images, nil
+}
+
+func decodeEntry(data []byte) (*image.NRGBA, error) {
+	if bytes.HasPrefix(data, pngMagic) {
+		img, err := png.Decode(bytes.NewReader(data))
+		if err != nil {
+			return nil, err
+		}
+		return toNRGBA(img), nil
+	}
+	return decodeDIB(data)
+}
+
+// decodeDIB decodes a 32-bit BI_RGB icon bitmap. The pixel alpha is used
+// as is; the AND mask is redundant for 32-bit images and ignored.
+func decodeDIB(data []byte) (*image.NRGBA, error) {
+	var header bitmapInfoHeader
+	if err := binary.Read(bytes.NewReader(data), binary.LittleEndian, &header); err != nil {
+		return nil, errors.New("invalid icon bitmap")
+	}
+	if header.BitCount != 32 || header.Compression != 0 {
+		return nil, errors.New("only 32-bit icon bitmaps are supported")
+	}
+
+	width := int(header.Width)
+	height := int(header.Height) / 2 // XOR image + AND mask
+	if width <= 0 || height <= 0 || width > 256 || height > 256 {
+		return nil, errors.New("invalid icon size")
+	}
+	if int(header.Size) > len(data) || len(data)-int(header.Size) < width*height*4 {
+		return nil, errors.New("truncated icon bitmap")
+	}
+	pixels := data[header.Size:]
+
+	img := image.NewNRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		row := pixels[(height-1-y)*width*4:] // bottom-up
+		for x := 0; x < width; x++ {
+			b, g, r, a := row[x*4], row[x*4+1], row[x*4+2], row[x*4+3]
+			i := img.PixOffset(x, y)
+			img.Pix[i], img.Pix[i+1], img.Pix[i+2], img.Pix[i+3] = r, g, b, a
+		}
+	}
+	return img, nil
+}
+
+// EncodeICO writes the images as an ICO file of 32-bit DIBs, which every
+// Windows version loads. Images must be at most 256x256.
+func EncodeICO(images []*image.NRGBA) ([]byte, error) {
+	if len(images) == 0 {
+		return nil, errors.New("no images")
+	}
+
+	var body bytes.Buffer
+	entries := make([]iconEntry, len(images))
+	offset := iconDirSize + iconEntrySize*len(images)
+
+	for i, img := range images {
+		width, height := img.Bounds().Dx(), img.Bounds().Dy()
+		if width > 256 || height > 256 {
+			return nil, errors.New("icon images must be at most 256x256")
+		}
+
+		dib := encodeDIB(img)
+		entries[i] = iconEntry{
+			Width:    uint8(width), // 256 wraps to 0 as the format wants
+			Height:   uint8(height),
+			Planes:   1,
+			BitCount: 32,
+			Size:     uint32(len(dib)),
+			Offset:   uint32(offset + body.Len()),
+		}
+		body.Write(dib)
+	}
+
+	var out bytes.Buffer
+	binary.Write(&out, binary.LittleEndian, iconDir{Type: 1, Count: uint16(len(images))})
+	for _, entry := range entries {
+		binary.Write(&out, binary.LittleEndian, entry)
+	}
+	out.Write(body.Bytes())
+	return out.Bytes(), nil
+}
+
+func encodeDIB(img *image.NRGBA) []byte {
+	bounds := img.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+	maskStride := ((width + 31) / 32) * 4 // 1 bit per pixel, rows padded to 32 bits
+
+	var buf bytes.Buffer
+	binary.Write(&buf, binary.LittleEndian, bitmapInfoHeader{
+		Size:      bmpHeaderSize,
+		Width:     int32(width),
+		Height:    int32(height * 2),
+		Planes:    1,
+		BitCount:  32,
+		SizeImage: uint32(width*height*4 + maskStride*height),
+	})
+
+	for y := height - 1; y >= 0; y-- {
+		for x := 0; x < width; x++ {
+			i := img.PixOffset(bounds.Min.X+x, bounds.Min.Y+y)
+			buf.Write([]byte{img.Pix[i+2], img.Pix[i+1], img.Pix[i], img.Pix[i+3]})
+		}
+	}
+
+	// AND mask: set bits are transparent
+	for y := height - 1; y >= 0; y-- {
+		row := make([]byte, maskStride)
+		for x := 0; x < width; x++ {
+			if img.NRGBAAt(bounds.Min.X+x, bounds.Min.Y+y).A == 0 {
+				row[x/8] |= 0x80 >> (x % 8)
+			}
+		}
+		buf.Write(row)
+	}
+	return buf.Bytes()
+}
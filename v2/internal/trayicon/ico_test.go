@@ -0,0 +1,131 @@
+package trayicon
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image"
+	"image/color"
+	"image/png"
+	"testing"
+)
+
+// testImage returns a size x size image with varied colors and alpha,
+// including fully transparent pixels
+func testImage(size int) *image.NRGBA {
+	img := image.NewNRGBA(image.Rect(0, 0, size, size))
+	for y := 0; y < size; y++ {
+		for x := 0; x < size; x++ {
+			img.SetNRGBA(x, y, color.NRGBA{
+				R: uint8(x * 255 / size),
+				G: uint8(y * 255 / size),
+				B: uint8((x + y) % 256),
+				A: uint8((x * y) % 256),
+			})
+		}
+	}
+	return img
+}
+
+func TestICORoundTrip(t *testing.T) {
+	images := []*image.NRGBA{testImage(16), testImage(32), testImage(48), testImage(256)}
+
+	data, err := EncodeICO(images)
+	if err != nil {
+		t.Fatalf("EncodeICO: %v", err)
+	}
+	decoded, err := DecodeICO(data)
+	if err != nil {
+		t.Fatalf("DecodeICO: %v", err)
+	}
+	if len(decoded) != len(images) {
+		t.Fatalf("decoded %d images, want %d", len(decoded), len(images))
+	}
+	for i, img := range decoded {
+		if img.Bounds() != images[i].Bounds() {
+			t.Errorf("image %d: bounds %v, want %v", i, img.Bounds(), images[i].Bounds())
+			continue
+		}
+		if !bytes.Equal(img.Pix, images[i].Pix) {
+			t.Errorf("image %d: pixels differ after round trip", i)
+		}
+	}
+}
+
+func TestDecodeICOPNGEntry(t *testing.T) {
+	want := testImage(24)
+	var pngData bytes.Buffer
+	if err := png.Encode(&pngData, want); err != nil {
+		t.Fatal(err)
+	}
+
+	var data bytes.Buffer
+	binary.Write(&data, binary.LittleEndian, iconDir{Type: 1, Count: 1})
+	binary.Write(&data, binary.LittleEndian, iconEntry{
+		Width: 24, Height: 24, Planes: 1, BitCount: 32,
+		Size:   uint32(pngData.Len()),
+		Offset: iconDirSize + iconEntrySize,
+	})
+	data.Write(pngData.Bytes())
+
+	images, err := DecodeICO(data.Bytes())
+	if err != nil {
+		t.Fatalf("DecodeICO: %v", err)
+	}
+	if len(images) != 1 || !bytes.Equal(images[0].Pix, want.Pix) {
+		t.Fatal("PNG entry not decoded to the original pixels")
+	}
+}
+
+func TestDecodeICORejectsMalformed(t *testing.T) {
+	valid, err := EncodeICO([]*image.NRGBA{testImage(16)})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// patch returns a copy of valid with b written at offset
+	patch := func(offset int, b ...byte) []byte {
+		out := append([]byte(nil), valid...)
+		copy(out[offset:], b)
+		return out
+	}
+	dib := iconDirSize + iconEntrySize // offset of the only bitmap
+
+	tests := []struct {
+		name string
+		data []byte
+	}{
+		{"empty", nil},
+		{"short header", valid[:4]},
+		{"not an icon", patch(2, 2, 0)}, // type 2 is a cursor
+		{"no images", patch(4, 0, 0)},
+		{"truncated directory", valid[:iconDirSize+iconEntrySize-1]},
+		{"more entries than directory", patch(4, 2, 0)},
+		{"truncated image", valid[:len(valid)-1]},
+		{"offset past end", patch(iconDirSize+12, 0xff, 0xff, 0xff, 0x7f)},
+		{"24-bit bitmap", patch(dib+14, 24, 0)},
+		{"compressed bitmap", patch(dib+16, 1)},
+		{"zero width", patch(dib+4, 0, 0, 0, 0)},
+		{"oversized bitmap", patch(dib+4, 0, 0x10, 0, 0)},
+		{"bitmap header past data", patch(dib, 0xff, 0xff, 0, 0)},
+		{"garbage", []byte("this is not an icon at all")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := DecodeICO(tt.data); err == nil {
+				t.Error("DecodeICO accepted malformed input")
+			}
+			if _, err := Render(tt.data, StatusError); err == nil {
+				t.Error("Render accepted malformed input")
+			}
+		})
+	}
+}
+
+func TestEncodeICORejects(t *testing.T) {
+	if _, err := EncodeICO(nil); err == nil {
+		t.Error("EncodeICO accepted no images")
+	}
+	if _, err := EncodeICO([]*image.NRGBA{testImage(257)}); err == nil {
+		t.Error("EncodeICO accepted an image larger than 256x256")
+	}
+}
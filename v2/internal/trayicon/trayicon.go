@@ -0,0 +1,136 @@
+// Package trayicon draws status overlays onto the tray icon. Compositing and
+// ICO encoding are pure Go, so the tray code only has to load the result.
+package trayicon
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"math"
+)
+
+// Status selects the overlay drawn onto the tray icon
+type Status int
+
+const (
+	StatusIdle     Status = iota // no overlay
+	StatusWatching               // session capture pending
+	StatusError                  // last switch or login failed
+	StatusUpdate                 // update available
+)
+
+// String returns the status name
+func (s Status) String() string {
+	switch s {
+	case StatusWatching:
+		return "watching"
+	case StatusError:
+		return "error"
+	case StatusUpdate:
+		return "update"
+	default:
+		return "idle"
+	}
+}
+
+// maxTraySize drops the large icon images; the tray never shows them
+const maxTraySize = 64
+
+var badgeColors = map[Status]color.NRGBA{
+	StatusWatching: {R: 0xf0, G: 0xb0, B: 0x20, A: 0xff}, // amber
+	StatusError:    {R: 0xe0, G: 0x30, B: 0x30, A: 0xff}, // red
+	StatusUpdate:   {R: 0x30, G: 0xa0, B: 0xf0, A: 0xff}, // blue
+}
+
+var badgeBorder = color.NRGBA{R: 0x10, G: 0x10, B: 0x10, A: 0xff}
+
+// Render returns the base ICO with the overlay for status drawn onto every
+// image up to 64x64
+func Render(base []byte, status Status) ([]byte, error) {
+	images, err := DecodeICO(base)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]*image.NRGBA, 0, len(images))
+	for _, img := range images {
+		if img.Bounds().Dx() > maxTraySize {
+			continue
+		}
+		out = append(out, Overlay(img, status))
+	}
+	if len(out) == 0 {
+		out = images // only large images; better too big than nothing
+	}
+	return EncodeICO(out)
+}
+
+// Overlay returns a copy of img with a round status badge in the bottom
+// right corner. StatusIdle returns an unchanged copy.
+func Overlay(img *image.NRGBA, status Status) *image.NRGBA {
+	out := toNRGBA(img)
+	fill, ok := badgeColors[status]
+	if !ok {
+		return out
+	}
+
+	bounds := out.Bounds()
+	size := float64(bounds.Dx())
+	radius := size * 0.22
+	border := math.Max(1, size/16)
+	cx := float64(bounds.Max.X) - radius - 0.5
+	cy := float64(bounds.Max.Y) - radius - 0.5
+
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			dist := math.Hypot(float64(x)-cx, float64(y)-cy)
+			// Coverage gives a one-pixel anti-aliased edge
+			outer := clamp(radius + 0.5 - dist)
+			if outer == 0 {
+				continue
+			}
+			inner := clamp(radius - border + 0.5 - dist)
+
+			blend(out, x, y, badgeBorder, outer)
+			blend(out, x, y, fill, inner)
+		}
+	}
+	return out
+}
+
+// blend draws c over the pixel at x, y with the given coverage
+func blend(img *image.NRGBA, x, y int, c color.NRGBA, coverage float64) {
+	if coverage == 0 {
+		return
+	}
+	dst := img.NRGBAAt(x, y)
+	srcA := float64(c.A) / 255 * coverage
+	dstA := float64(dst.A) / 255
+	outA := srcA + dstA*(1-srcA)
+	if outA == 0 {
+		return
+	}
+
+	mix := func(s, d uint8) uint8 {
+		v := (float64(s)*srcA + float64(d)*dstA*(1-srcA)) / outA
+		return uint8(math.Round(v))
+	}
+	img.SetNRGBA(x, y, color.NRGBA{
+		R: mix(c.R, dst.R),
+		G: mix(c.G, dst.G),
+		B: mix(c.B, dst.B),
+		A: uint8(math.Round(outA * 255)),
+	})
+}
+
+func clamp(v float64) float64 {
+	return math.Max(0, math.Min(1, v))
+}
+
+// toNRGBA returns a copy of img as NRGBA with bounds starting at 0,0
+func toNRGBA(img image.Image) *image.NRGBA {
+	bounds := img.Bounds()
+	out := image.NewNRGBA(image.Rect(0, 0, bounds.Dx(), bounds.Dy()))
+	draw.Draw(out, out.Bounds(), img, bounds.Min, draw.Src)
+	return out
+}
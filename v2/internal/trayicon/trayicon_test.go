@@ -0,0 +1,87 @@
+package trayicon
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"testing"
+)
+
+var baseColor = color.NRGBA{R: 0x20, G: 0x80, B: 0x20, A: 0xff}
+
+func solidImage(size int, c color.NRGBA) *image.NRGBA {
+	img := image.NewNRGBA(image.Rect(0, 0, size, size))
+	for i := 0; i < len(img.Pix); i += 4 {
+		img.Pix[i], img.Pix[i+1], img.Pix[i+2], img.Pix[i+3] = c.R, c.G, c.B, c.A
+	}
+	return img
+}
+
+func TestOverlayBadge(t *testing.T) {
+	// For 32x32 the badge has radius 7.04 and a 2px border around the
+	// center 24.46, 24.46; 30,24 lies fully inside the border ring
+	tests := []struct {
+		status Status
+		center color.NRGBA // inside the badge
+		edge   color.NRGBA // on the border ring
+	}{
+		{StatusIdle, baseColor, baseColor},
+		{StatusWatching, badgeColors[StatusWatching], badgeBorder},
+		{StatusError, badgeColors[StatusError], badgeBorder},
+		{StatusUpdate, badgeColors[StatusUpdate], badgeBorder},
+	}
+	for _, tt := range tests {
+		t.Run(tt.status.String(), func(t *testing.T) {
+			base := solidImage(32, baseColor)
+			out := Overlay(base, tt.status)
+
+			if got := out.NRGBAAt(24, 24); got != tt.center {
+				t.Errorf("badge center = %v, want %v", got, tt.center)
+			}
+			if got := out.NRGBAAt(30, 24); got != tt.edge {
+				t.Errorf("badge border = %v, want %v", got, tt.edge)
+			}
+			for _, p := range []image.Point{{0, 0}, {31, 0}, {0, 31}, {16, 16}} {
+				if got := out.NRGBAAt(p.X, p.Y); got != baseColor {
+					t.Errorf("pixel %v outside the badge = %v, want %v", p, got, baseColor)
+				}
+			}
+			if base.NRGBAAt(24, 24) != baseColor {
+				t.Error("Overlay modified its input")
+			}
+		})
+	}
+}
+
+func TestOverlayIdleIsCopy(t *testing.T) {
+	base := testImage(16)
+	out := Overlay(base, StatusIdle)
+	if !bytes.Equal(out.Pix, base.Pix) {
+		t.Error("idle overlay changed pixels")
+	}
+	if &out.Pix[0] == &base.Pix[0] {
+		t.Error("idle overlay returned the input instead of a copy")
+	}
+}
+
+func TestRenderDropsLargeImages(t *testing.T) {
+	base, err := EncodeICO([]*image.NRGBA{solidImage(32, baseColor), solidImage(128, baseColor)})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := Render(base, StatusWatching)
+	if err != nil {
+		t.Fatalf("Render: %v", err)
+	}
+	images, err := DecodeICO(data)
+	if err != nil {
+		t.Fatalf("DecodeICO: %v", err)
+	}
+	if len(images) != 1 || images[0].Bounds().Dx() != 32 {
+		t.Fatalf("rendered %d images, want only the 32x32 one", len(images))
+	}
+	if got := images[0].NRGBAAt(24, 24); got != badgeColors[StatusWatching] {
+		t.Errorf("badge center = %v, want %v", got, badgeColors[StatusWatching])
+	}
+}
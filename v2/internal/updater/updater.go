@@ -3,6 +3,7 @@ package updater
 import (
 	"encoding/json"
 	"fmt"
+	"log/slog"
 	"net/http"
 	"strconv"
 	"strings"
@@ -34,16 +35,26 @@ type ghRelease struct {
 
 // CheckAsync runs an update check in a background goroutine.
 // The callback is only called when at least one update is found.
-// Errors (network, parse, etc.) are silently ignored.
+// Errors (network, parse, etc.) are only logged.
 func CheckAsync(cb func(Result)) {
 	go func() {
+		slog.Info("update check started", "current", CurrentVersion)
 		result, err := check()
 		if err != nil {
+			slog.Warn("update check failed", "err", err)
 			return
 		}
-		if result.StableUpdate != nil || result.BetaUpdate != nil {
-			cb(result)
+		if result.StableUpdate == nil && result.BetaUpdate == nil {
+			slog.Info("no update available")
+			return
+		}
+		if result.StableUpdate != nil {
+			slog.Info("update available", "version", result.StableUpdate.Version)
+		}
+		if result.BetaUpdate != nil {
+			slog.Info("beta update available", "version", result.BetaUpdate.Version)
 		}
+		cb(result)
 	}()
 }
 
@@ -2,33 +2,58 @@ package main
 
 import (
 	"embed"
+	"log/slog"
+	"os"
+	"runtime"
 
 	"github.com/wailsapp/wails/v2"
 	"github.com/wailsapp/wails/v2/pkg/options"
 	"github.com/wailsapp/wails/v2/pkg/options/assetserver"
 	"github.com/wailsapp/wails/v2/pkg/options/windows"
-	"github.com/wailsapp/wails/v2/pkg/runtime"
 
 	"tarkov-account-switcher/internal/accounts"
+	"tarkov-account-switcher/internal/actions"
+	"tarkov-account-switcher/internal/cli"
 	"tarkov-account-switcher/internal/config"
+	"tarkov-account-switcher/internal/logging"
+	"tarkov-account-switcher/internal/updater"
 )
 
 //go:embed all:frontend/dist
 var assets embed.FS
 
 func main() {
+	// --data-dir must be known before any path is resolved
+	dataDir, args := config.ParseDataDirFlag(os.Args[1:])
+	config.SetDataDirFlag(dataDir)
+
 	// Ensure data directory exists
 	if err := config.EnsureDataDir(); err != nil {
 		panic(err)
 	}
 
+	// The app works without a log if it cannot be opened
+	logging.Init()
+	paths := config.GetPaths()
+	slog.Info("starting", "version", updater.CurrentVersion, "os", runtime.GOOS,
+		"dataDir", paths.DataDir, "dataDirSource", paths.DataDirSource, "wine", config.IsWine())
+
 	// Initialize encryption key
 	if _, err := accounts.GetOrCreateKey(); err != nil {
 		panic(err)
 	}
 
+	// Subcommands run headless without opening a window
+	if cli.IsCommand(args) {
+		os.Exit(cli.Run(args))
+	}
+
 	app := NewApp()
 
+	// Actions like --switch run once the app is up; a running instance gets
+	// them forwarded through the single-instance lock instead
+	app.pendingAction, app.pendingActionErr = actions.ParseArgs(args)
+
 	err := wails.Run(&options.App{
 		Title:             "Tarkov Account Switcher",
 		Width:             800,
@@ -49,8 +74,7 @@ func main() {
 		SingleInstanceLock: &options.SingleInstanceLock{
 			UniqueId: "TarkovAccountSwitcher-v2-a3f8e921",
 			OnSecondInstanceLaunch: func(data options.SecondInstanceData) {
-				runtime.WindowUnminimise(app.ctx)
-				runtime.WindowShow(app.ctx)
+				app.onSecondInstance(data.Args)
 			},
 		},
 		Windows: &windows.Options{
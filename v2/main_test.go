@@ -0,0 +1,24 @@
+package main
+
+import (
+	"os"
+	"testing"
+
+	"tarkov-account-switcher/internal/config"
+	"tarkov-account-switcher/internal/i18n"
+)
+
+// TestMain keeps the tests away from the real data directory and fixes the
+// language the expected strings are built in
+func TestMain(m *testing.M) {
+	dir, err := os.MkdirTemp("", "tarkov-switcher-test-")
+	if err != nil {
+		panic(err)
+	}
+	os.Setenv(config.DataDirEnv, dir)
+	i18n.SetLanguage("en")
+
+	code := m.Run()
+	os.RemoveAll(dir)
+	os.Exit(code)
+}
@@ -0,0 +1,18 @@
+//go:build !windows
+
+package main
+
+// No system tray outside Windows yet - the window stays the only UI.
+
+// startTray is a no-op outside Windows
+func startTray(iconData []byte, tooltip string, onShow func(), onQuit func(), menu func() []trayMenuItem, onSwitch func(accountID string)) {
+}
+
+// stopTray is a no-op outside Windows
+func stopTray() {}
+
+// setTrayStatus is a no-op outside Windows
+func setTrayStatus(iconData []byte, tooltip string) bool { return false }
+
+// setWindowIcon is a no-op outside Windows - Wails uses the embedded icon
+func setWindowIcon(iconData []byte) {}
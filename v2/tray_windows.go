@@ -6,11 +6,12 @@ import (
 	"os"
 	"path/filepath"
 	"runtime"
+	"strings"
 	"sync"
 	"syscall"
 	"unsafe"
 
-	"tarkov-account-switcher/internal/i18n"
+	"tarkov-account-switcher/internal/notify"
 )
 
 // ============================================================
@@ -31,25 +32,29 @@ const (
 	wmLButtonDblClk = 0x0203
 
 	nimAdd    = 0x00000000
+	nimModify = 0x00000001
 	nimDelete = 0x00000002
 
 	nifMessage = 0x00000001
 	nifIcon    = 0x00000002
 	nifTip     = 0x00000004
+	nifInfo    = 0x00000010
+
+	niifInfo  = 0x00000001
+	niifError = 0x00000003
 
 	imageIcon      = 1
 	lrLoadFromFile = 0x0010
 	lrDefaultSize  = 0x0040
 
 	mfString    = 0x0000
+	mfGrayed    = 0x0001
+	mfChecked   = 0x0008
 	mfSeparator = 0x0800
 
 	tpmLeftAlign   = 0x0000
 	tpmRightButton = 0x0002
 	tpmReturnCmd   = 0x0100
-
-	idmOpen = 1001
-	idmQuit = 1002
 )
 
 type notifyIconData struct {
@@ -60,6 +65,14 @@ type notifyIconData struct {
 	UCallbackMessage uint32
 	HIcon            uintptr
 	SzTip            [128]uint16
+	DwState          uint32
+	DwStateMask      uint32
+	SzInfo           [256]uint16
+	UVersion         uint32
+	SzInfoTitle      [64]uint16
+	DwInfoFlags      uint32
+	GuidItem         [16]byte
+	HBalloonIcon     uintptr
 }
 
 type wndClassExW struct {
@@ -110,6 +123,7 @@ var (
 	procGetCursorPos        = user32dll.NewProc("GetCursorPos")
 	procSetForegroundWindow = user32dll.NewProc("SetForegroundWindow")
 	procLoadImageW          = user32dll.NewProc("LoadImageW")
+	procDestroyIcon         = user32dll.NewProc("DestroyIcon")
 	procSendMessageW        = user32dll.NewProc("SendMessageW")
 	procFindWindowW         = user32dll.NewProc("FindWindowW")
 	procEnumWindows         = user32dll.NewProc("EnumWindows")
@@ -123,12 +137,15 @@ var (
 
 // Tray holds system tray state
 type Tray struct {
-	hwnd    uintptr
-	nid     notifyIconData
-	onShow  func()
-	onQuit  func()
-	mu      sync.Mutex
-	running bool
+	hwnd     uintptr
+	nid      notifyIconData
+	onShow   func()
+	onQuit   func()
+	onSwitch func(accountID string)
+	menu     func() []trayMenuItem
+	items    []trayMenuItem // menu currently shown, to resolve WM_COMMAND
+	mu       sync.Mutex
+	running  bool
 }
 
 var globalTray Tray
@@ -149,12 +166,20 @@ func trayWndProc(hwnd uintptr, umsg uint32, wParam uintptr, lParam uintptr) uint
 
 	case wmCommand:
 		cmdID := int(wParam & 0xFFFF)
-		switch cmdID {
-		case idmOpen:
+		item, ok := findTrayMenuItem(globalTray.items, cmdID)
+		if !ok {
+			return 0
+		}
+		switch {
+		case item.AccountID != "":
+			if globalTray.onSwitch != nil {
+				go globalTray.onSwitch(item.AccountID)
+			}
+		case item.ID == trayIDOpen:
 			if globalTray.onShow != nil {
 				go globalTray.onShow()
 			}
-		case idmQuit:
+		case item.ID == trayIDQuit:
 			if globalTray.onQuit != nil {
 				go globalTray.onQuit()
 			}
@@ -176,12 +201,26 @@ func showTrayContextMenu(hwnd uintptr) {
 		return
 	}
 
-	openText, _ := syscall.UTF16PtrFromString(i18n.T(i18n.TrayOpen))
-	quitText, _ := syscall.UTF16PtrFromString(i18n.T(i18n.TrayQuit))
+	if globalTray.menu != nil {
+		globalTray.items = globalTray.menu()
+	}
+	for _, item := range globalTray.items {
+		if item.Separator {
+			procAppendMenuW.Call(hMenu, mfSeparator, 0, 0)
+			continue
+		}
 
-	procAppendMenuW.Call(hMenu, mfString, idmOpen, uintptr(unsafe.Pointer(openText)))
-	procAppendMenuW.Call(hMenu, mfSeparator, 0, 0)
-	procAppendMenuW.Call(hMenu, mfString, idmQuit, uintptr(unsafe.Pointer(quitText)))
+		flags := uintptr(mfString)
+		if item.Checked {
+			flags |= mfChecked
+		}
+		if item.Disabled {
+			flags |= mfGrayed
+		}
+		// A single & would underline the next character of an account name
+		text, _ := syscall.UTF16PtrFromString(strings.ReplaceAll(item.Label, "&", "&&"))
+		procAppendMenuW.Call(hMenu, flags, uintptr(item.ID), uintptr(unsafe.Pointer(text)))
+	}
 
 	var pt point
 	procGetCursorPos.Call(uintptr(unsafe.Pointer(&pt)))
@@ -202,10 +241,20 @@ func showTrayContextMenu(hwnd uintptr) {
 	procPostMessageW.Call(hwnd, 0, 0, 0)
 }
 
-// startTray creates the system tray icon on a dedicated OS thread
-func startTray(iconData []byte, tooltip string, onShow func(), onQuit func()) {
+// startTray creates the system tray icon on a dedicated OS thread.
+// menu builds the context menu each time it opens; onSwitch is called
+// with the account picked from it.
+func startTray(iconData []byte, tooltip string, onShow func(), onQuit func(), menu func() []trayMenuItem, onSwitch func(accountID string)) {
 	globalTray.onShow = onShow
 	globalTray.onQuit = onQuit
+	globalTray.menu = menu
+	globalTray.onSwitch = onSwitch
+
+	// Notifications show as balloons (toasts on Windows 10+) on the tray icon
+	notify.SetNotifier(notify.NotifierFunc(func(title, message string, isError bool) error {
+		showTrayNotification(title, message, isError)
+		return nil
+	}))
 
 	go func() {
 		// Lock THIS goroutine to its OS thread.
@@ -235,26 +284,9 @@ func startTray(iconData []byte, tooltip string, onShow func(), onQuit func()) {
 		)
 		globalTray.hwnd = hwnd
 
-		// Write icon to temp file so LoadImage can read it
-		var hIcon uintptr
-		if len(iconData) > 0 {
-			tmpDir := filepath.Join(os.TempDir(), "TarkovAccountSwitcher")
-			os.MkdirAll(tmpDir, 0755)
-			iconPath := filepath.Join(tmpDir, "tray.ico")
-			if err := os.WriteFile(iconPath, iconData, 0644); err == nil {
-				iconPathW, _ := syscall.UTF16PtrFromString(iconPath)
-				hIcon, _, _ = procLoadImageW.Call(
-					0,
-					uintptr(unsafe.Pointer(iconPathW)),
-					imageIcon,
-					0, 0,
-					lrLoadFromFile|lrDefaultSize,
-				)
-			}
-		}
+		hIcon := loadTrayIcon(iconData, "tray.ico")
 
 		// Create the notify icon
-		tipW, _ := syscall.UTF16FromString(tooltip)
 		nid := notifyIconData{
 			HWnd:             hwnd,
 			UID:              1,
@@ -263,7 +295,7 @@ func startTray(iconData []byte, tooltip string, onShow func(), onQuit func()) {
 			HIcon:            hIcon,
 		}
 		nid.CbSize = uint32(unsafe.Sizeof(nid))
-		copy(nid.SzTip[:], tipW)
+		setTip(&nid, tooltip)
 
 		procShellNotifyIconW.Call(nimAdd, uintptr(unsafe.Pointer(&nid)))
 
@@ -287,6 +319,67 @@ func startTray(iconData []byte, tooltip string, onShow func(), onQuit func()) {
 	}()
 }
 
+// loadTrayIcon writes the icon to a temp file so LoadImage can read it.
+// Returns 0 if the icon could not be loaded.
+func loadTrayIcon(iconData []byte, fileName string) uintptr {
+	if len(iconData) == 0 {
+		return 0
+	}
+	tmpDir := filepath.Join(os.TempDir(), "TarkovAccountSwitcher")
+	os.MkdirAll(tmpDir, 0755)
+	iconPath := filepath.Join(tmpDir, fileName)
+	if err := os.WriteFile(iconPath, iconData, 0644); err != nil {
+		return 0
+	}
+
+	iconPathW, _ := syscall.UTF16PtrFromString(iconPath)
+	hIcon, _, _ := procLoadImageW.Call(
+		0,
+		uintptr(unsafe.Pointer(iconPathW)),
+		imageIcon,
+		0, 0,
+		lrLoadFromFile|lrDefaultSize,
+	)
+	return hIcon
+}
+
+// setTip copies the tooltip into the notify icon data. buildTrayState keeps
+// it within trayTipMax, so the truncation here is only a safeguard.
+func setTip(nid *notifyIconData, tooltip string) {
+	tipW, _ := syscall.UTF16FromString(tooltip)
+	nid.SzTip = [128]uint16{}
+	copy(nid.SzTip[:len(nid.SzTip)-1], tipW)
+}
+
+// setTrayStatus replaces the tray icon and tooltip. Reports false if the
+// tray is not running (yet).
+func setTrayStatus(iconData []byte, tooltip string) bool {
+	globalTray.mu.Lock()
+	defer globalTray.mu.Unlock()
+
+	if !globalTray.running {
+		return false
+	}
+
+	nid := globalTray.nid
+	nid.UFlags = nifTip
+	setTip(&nid, tooltip)
+
+	oldIcon := uintptr(0)
+	if hIcon := loadTrayIcon(iconData, "tray-status.ico"); hIcon != 0 {
+		oldIcon = nid.HIcon
+		nid.HIcon = hIcon
+		nid.UFlags |= nifIcon
+	}
+
+	procShellNotifyIconW.Call(nimModify, uintptr(unsafe.Pointer(&nid)))
+	if oldIcon != 0 {
+		procDestroyIcon.Call(oldIcon)
+	}
+	globalTray.nid = nid
+	return true
+}
+
 // stopTray removes the tray icon and stops the message loop
 func stopTray() {
 	globalTray.mu.Lock()
@@ -303,6 +396,31 @@ func stopTray() {
 	globalTray.running = false
 }
 
+// showTrayNotification shows a balloon (a toast on Windows 10+) from the tray icon
+func showTrayNotification(title, message string, isError bool) {
+	globalTray.mu.Lock()
+	defer globalTray.mu.Unlock()
+
+	if !globalTray.running {
+		return
+	}
+
+	nid := globalTray.nid
+	nid.UFlags = nifInfo
+	nid.DwInfoFlags = niifInfo
+	if isError {
+		nid.DwInfoFlags = niifError
+	}
+
+	// Leave room for the terminating zero when truncating
+	titleW, _ := syscall.UTF16FromString(title)
+	messageW, _ := syscall.UTF16FromString(message)
+	copy(nid.SzInfoTitle[:len(nid.SzInfoTitle)-1], titleW)
+	copy(nid.SzInfo[:len(nid.SzInfo)-1], messageW)
+
+	procShellNotifyIconW.Call(nimModify, uintptr(unsafe.Pointer(&nid)))
+}
+
 // ============================================================
 // Window icon setter — finds the Wails window and sets its icon
 // via WM_SETICON, bypassing the unreliable resource system.
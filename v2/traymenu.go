@@ -0,0 +1,64 @@
+package main
+
+import (
+	"tarkov-account-switcher/internal/accounts"
+	"tarkov-account-switcher/internal/i18n"
+)
+
+// Tray menu command IDs. Account entries are numbered from trayIDAccount on.
+const (
+	trayIDOpen    = 1001
+	trayIDQuit    = 1002
+	trayIDAccount = 2000
+)
+
+// trayMenuItem is one entry of the tray context menu. The platform tray
+// code only renders these; what the menu contains is decided here.
+type trayMenuItem struct {
+	ID        int
+	Label     string
+	AccountID string // quick-switch entries only
+	Checked   bool   // account logged in to the launcher
+	Disabled  bool
+	Separator bool
+}
+
+// buildTrayMenu lists the accounts in the user's order for quick switching,
+// followed by Open and Quit. activeID is the account logged in to the
+// launcher ("" if none).
+func buildTrayMenu(accs []accounts.Account, activeID string) []trayMenuItem {
+	items := make([]trayMenuItem, 0, len(accs)+4)
+
+	for i, acc := range accs {
+		label := acc.Name
+		if !acc.HasSession() {
+			label = i18n.TF(i18n.TrayNoSession, map[string]string{"name": acc.Name})
+		}
+		items = append(items, trayMenuItem{
+			ID:        trayIDAccount + i,
+			Label:     label,
+			AccountID: acc.ID,
+			Checked:   acc.ID == activeID,
+		})
+	}
+	if len(accs) == 0 {
+		items = append(items, trayMenuItem{Label: i18n.T(i18n.TrayNoAccounts), Disabled: true})
+	}
+
+	return append(items,
+		trayMenuItem{Separator: true},
+		trayMenuItem{ID: trayIDOpen, Label: i18n.T(i18n.TrayOpen)},
+		trayMenuItem{Separator: true},
+		trayMenuItem{ID: trayIDQuit, Label: i18n.T(i18n.TrayQuit)},
+	)
+}
+
+// findTrayMenuItem returns the entry with the given command ID
+func findTrayMenuItem(items []trayMenuItem, id int) (trayMenuItem, bool) {
+	for _, item := range items {
+		if !item.Separator && item.ID == id {
+			return item, true
+		}
+	}
+	return trayMenuItem{}, false
+}
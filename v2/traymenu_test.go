@@ -0,0 +1,94 @@
+package main
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"tarkov-account-switcher/internal/accounts"
+	"tarkov-account-switcher/internal/i18n"
+)
+
+func TestBuildTrayMenu(t *testing.T) {
+	mainAcc := accounts.Account{ID: "1", Name: "Main", LauncherSession: json.RawMessage(`{"at":"x"}`)}
+	mule := accounts.Account{ID: "2", Name: "Mule"}
+	alt := accounts.Account{ID: "3", Name: "Alt", EncryptedSession: "c2Vzc2lvbg=="}
+	rejected := accounts.Account{ID: "4", Name: "Old", EncryptedSession: "c2Vzc2lvbg==", SessionInvalid: true}
+
+	footer := []trayMenuItem{
+		{Separator: true},
+		{ID: trayIDOpen, Label: i18n.T(i18n.TrayOpen)},
+		{Separator: true},
+		{ID: trayIDQuit, Label: i18n.T(i18n.TrayQuit)},
+	}
+	noSession := func(name string) string {
+		return i18n.TF(i18n.TrayNoSession, map[string]string{"name": name})
+	}
+
+	tests := []struct {
+		name     string
+		accounts []accounts.Account
+		activeID string
+		want     []trayMenuItem
+	}{
+		{
+			name: "no accounts",
+			want: append([]trayMenuItem{
+				{Label: i18n.T(i18n.TrayNoAccounts), Disabled: true},
+			}, footer...),
+		},
+		{
+			name:     "saved order, active account checked",
+			accounts: []accounts.Account{alt, mainAcc, mule},
+			activeID: "1",
+			want: append([]trayMenuItem{
+				{ID: trayIDAccount, Label: "Alt", AccountID: "3"},
+				{ID: trayIDAccount + 1, Label: "Main", AccountID: "1", Checked: true},
+				{ID: trayIDAccount + 2, Label: noSession("Mule"), AccountID: "2"},
+			}, footer...),
+		},
+		{
+			name:     "active account without session is checked and marked",
+			accounts: []accounts.Account{mainAcc, mule},
+			activeID: "2",
+			want: append([]trayMenuItem{
+				{ID: trayIDAccount, Label: "Main", AccountID: "1"},
+				{ID: trayIDAccount + 1, Label: noSession("Mule"), AccountID: "2", Checked: true},
+			}, footer...),
+		},
+		{
+			name:     "rejected session counts as none, unknown login checks nothing",
+			accounts: []accounts.Account{rejected},
+			activeID: "99",
+			want: append([]trayMenuItem{
+				{ID: trayIDAccount, Label: noSession("Old"), AccountID: "4"},
+			}, footer...),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := buildTrayMenu(tt.accounts, tt.activeID)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("buildTrayMenu()\n got %+v\nwant %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFindTrayMenuItem(t *testing.T) {
+	items := buildTrayMenu([]accounts.Account{{ID: "1", Name: "Main"}, {ID: "2", Name: "Mule"}}, "")
+
+	if item, ok := findTrayMenuItem(items, trayIDAccount+1); !ok || item.AccountID != "2" {
+		t.Errorf("account entry: got %+v, %v", item, ok)
+	}
+	if item, ok := findTrayMenuItem(items, trayIDQuit); !ok || item.Label != i18n.T(i18n.TrayQuit) {
+		t.Errorf("quit entry: got %+v, %v", item, ok)
+	}
+	// Separators carry ID 0 and must never match
+	if _, ok := findTrayMenuItem(items, 0); ok {
+		t.Error("ID 0 matched a separator")
+	}
+	if _, ok := findTrayMenuItem(items, trayIDAccount+2); ok {
+		t.Error("matched an account entry that does not exist")
+	}
+}
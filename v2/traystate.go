@@ -0,0 +1,88 @@
+package main
+
+import (
+	"strings"
+	"unicode/utf16"
+
+	"tarkov-account-switcher/internal/accounts"
+	"tarkov-account-switcher/internal/i18n"
+	"tarkov-account-switcher/internal/service"
+	"tarkov-account-switcher/internal/trayicon"
+	"tarkov-account-switcher/internal/updater"
+)
+
+// trayState is what the tray icon and tooltip show
+type trayState struct {
+	Status  trayicon.Status
+	Tooltip string
+}
+
+// trayInputs is everything the tray state is derived from
+type trayInputs struct {
+	Login        service.LoginDTO
+	WatchingName string // account the session watcher waits for ("" if idle)
+	Failed       bool   // last switch or capture failed
+	Update       string // available update version ("" if none)
+}
+
+// trayTipMax is how many UTF-16 units of the tooltip the tray shows
+const trayTipMax = 127
+
+// readTrayInputs reads the launcher login (masked in streamer mode) and the
+// session watcher; failed and update are tracked from events by the caller
+func readTrayInputs(failed bool, update string) trayInputs {
+	in := trayInputs{Failed: failed, Update: update}
+	in.Login, _ = service.CurrentLogin()
+	if watcher := service.WatcherStatus(); watcher.Watching {
+		in.WatchingName = "?"
+		if acc, err := accounts.GetAccountByID(watcher.AccountID); err == nil && acc != nil {
+			in.WatchingName = acc.Name
+		}
+	}
+	return in
+}
+
+// buildTrayState derives the tray icon status and tooltip. The login email
+// is already masked in streamer mode; a failure outranks a pending capture,
+// which outranks an update. A name too long for the tooltip is shortened
+// so the status lines below it stay visible.
+func buildTrayState(in trayInputs) trayState {
+	name := in.Login.AccountName
+	if name == "" && in.Login.LoggedIn {
+		name = in.Login.Email
+	}
+
+	state := buildTrayStateFor(in, name)
+	runes := []rune(name)
+	for n := len(runes) - 1; n >= 0 && len(utf16.Encode([]rune(state.Tooltip))) > trayTipMax; n-- {
+		state = buildTrayStateFor(in, string(runes[:n])+"…")
+	}
+	return state
+}
+
+// buildTrayStateFor is buildTrayState showing the login as name
+func buildTrayStateFor(in trayInputs, name string) trayState {
+	lines := []string{"Tarkov Account Switcher " + updater.CurrentVersion}
+
+	if name != "" {
+		lines = append(lines, i18n.TF(i18n.TipLoggedIn, map[string]string{"name": name}))
+	} else {
+		lines = append(lines, i18n.T(i18n.TipLoggedOut))
+	}
+
+	status := trayicon.StatusIdle
+	if in.Update != "" {
+		status = trayicon.StatusUpdate
+		lines = append(lines, i18n.TF(i18n.TipUpdate, map[string]string{"version": in.Update}))
+	}
+	if in.WatchingName != "" {
+		status = trayicon.StatusWatching
+		lines = append(lines, i18n.TF(i18n.TipWatching, map[string]string{"name": in.WatchingName}))
+	}
+	if in.Failed {
+		status = trayicon.StatusError
+		lines = append(lines, i18n.T(i18n.TipFailed))
+	}
+
+	return trayState{Status: status, Tooltip: strings.Join(lines, "\n")}
+}
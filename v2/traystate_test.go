@@ -0,0 +1,149 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"unicode/utf16"
+
+	"tarkov-account-switcher/internal/config"
+	"tarkov-account-switcher/internal/i18n"
+	"tarkov-account-switcher/internal/service"
+	"tarkov-account-switcher/internal/trayicon"
+	"tarkov-account-switcher/internal/updater"
+)
+
+func TestBuildTrayState(t *testing.T) {
+	title := "Tarkov Account Switcher " + updater.CurrentVersion
+	loggedIn := func(name string) string {
+		return i18n.TF(i18n.TipLoggedIn, map[string]string{"name": name})
+	}
+	watching := i18n.TF(i18n.TipWatching, map[string]string{"name": "Mule"})
+	update := i18n.TF(i18n.TipUpdate, map[string]string{"version": "9.9.9"})
+	mainLogin := service.LoginDTO{LoggedIn: true, Email: "player@example.com", AccountID: "1", AccountName: "Main"}
+
+	tests := []struct {
+		name   string
+		in     trayInputs
+		status trayicon.Status
+		lines  []string
+	}{
+		{
+			name:   "logged out",
+			status: trayicon.StatusIdle,
+			lines:  []string{title, i18n.T(i18n.TipLoggedOut)},
+		},
+		{
+			name:   "saved account shows its name, not the email",
+			in:     trayInputs{Login: mainLogin},
+			status: trayicon.StatusIdle,
+			lines:  []string{title, loggedIn("Main")},
+		},
+		{
+			name:   "unknown login shows the email",
+			in:     trayInputs{Login: service.LoginDTO{LoggedIn: true, Email: "other@example.com"}},
+			status: trayicon.StatusIdle,
+			lines:  []string{title, loggedIn("other@example.com")},
+		},
+		{
+			name:   "pending capture",
+			in:     trayInputs{Login: mainLogin, WatchingName: "Mule"},
+			status: trayicon.StatusWatching,
+			lines:  []string{title, loggedIn("Main"), watching},
+		},
+		{
+			name:   "update",
+			in:     trayInputs{Update: "9.9.9"},
+			status: trayicon.StatusUpdate,
+			lines:  []string{title, i18n.T(i18n.TipLoggedOut), update},
+		},
+		{
+			name:   "failure outranks capture and update",
+			in:     trayInputs{Login: mainLogin, WatchingName: "Mule", Failed: true, Update: "9.9.9"},
+			status: trayicon.StatusError,
+			lines:  []string{title, loggedIn("Main"), update, watching, i18n.T(i18n.TipFailed)},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := buildTrayState(tt.in)
+			if got.Status != tt.status {
+				t.Errorf("status = %v, want %v", got.Status, tt.status)
+			}
+			if want := strings.Join(tt.lines, "\n"); got.Tooltip != want {
+				t.Errorf("tooltip\n got %q\nwant %q", got.Tooltip, want)
+			}
+		})
+	}
+}
+
+// TestTrayStreamerMode feeds the raw launcher login through the inputs the
+// tray reads, so the email must be masked on the way
+func TestTrayStreamerMode(t *testing.T) {
+	const email = "player@example.com"
+	writeLauncherLogin(t, email)
+	defer config.SetStreamerMode(false)
+
+	for _, streamer := range []bool{false, true} {
+		if err := config.SetStreamerMode(streamer); err != nil {
+			t.Fatal(err)
+		}
+		tooltip := buildTrayState(readTrayInputs(false, "")).Tooltip
+
+		if shown := strings.Contains(tooltip, email); shown == streamer {
+			t.Errorf("streamer mode %v: tooltip %q shows the email: %v", streamer, tooltip, shown)
+		}
+		if streamer && !strings.Contains(tooltip, config.HideEmail(email)) {
+			t.Errorf("streamer mode: tooltip %q lacks the masked email", tooltip)
+		}
+	}
+}
+
+// writeLauncherLogin points the launcher folders into a temp dir (Windows
+// and Wine) and writes a launcher settings file logged in as email
+func writeLauncherLogin(t *testing.T, email string) {
+	t.Helper()
+	dir := t.TempDir()
+	t.Setenv("APPDATA", dir)
+	wine := config.GetSettings().Wine
+	config.GetSettings().Wine.Prefix, config.GetSettings().Wine.User = dir, "test"
+	t.Cleanup(func() { config.GetSettings().Wine = wine })
+
+	path := config.LauncherSettingsPath()
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		t.Fatal(err)
+	}
+	data := `{"login":"` + email + `","at":"access","rt":"refresh"}`
+	if err := os.WriteFile(path, []byte(data), 0600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestBuildTrayStateLongName(t *testing.T) {
+	failed := i18n.T(i18n.TipFailed)
+
+	tests := []struct {
+		name  string
+		login service.LoginDTO
+	}{
+		{"account name", service.LoginDTO{LoggedIn: true, AccountName: strings.Repeat("Mule", 50)}},
+		{"email", service.LoginDTO{LoggedIn: true, Email: strings.Repeat("x", 150) + "@example.com"}},
+		{"wide runes", service.LoginDTO{LoggedIn: true, AccountName: strings.Repeat("😀", 100)}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tooltip := buildTrayState(trayInputs{Login: tt.login, Failed: true, Update: "9.9.9"}).Tooltip
+
+			if n := len(utf16.Encode([]rune(tooltip))); n > trayTipMax {
+				t.Errorf("tooltip has %d UTF-16 units, want at most %d", n, trayTipMax)
+			}
+			if !strings.HasSuffix(tooltip, "\n"+failed) {
+				t.Errorf("tooltip %q lost the failure line", tooltip)
+			}
+			if !strings.Contains(tooltip, "…") {
+				t.Errorf("tooltip %q does not mark the shortened name", tooltip)
+			}
+		})
+	}
+}